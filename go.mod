@@ -11,7 +11,6 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/iam v0.31.0
 	github.com/aws/smithy-go v0.5.0
 	github.com/fatih/color v1.7.0
-	github.com/gosimple/slug v1.9.0
 	github.com/gregjones/httpcache v0.0.0-20190611155906-901d90724c79 // indirect
 	github.com/mattn/go-colorable v0.1.6 // indirect
 	github.com/mattn/go-isatty v0.0.12
@@ -20,8 +19,12 @@ require (
 	github.com/slack-go/slack v0.8.0
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/viper v1.7.0
-	github.com/stretchr/testify v1.6.1
+	github.com/stretchr/testify v1.7.0
 	github.com/tj/go-spin v1.1.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	go.uber.org/multierr v1.5.0 // indirect
 	k8s.io/api v0.20.1
 	k8s.io/apimachinery v0.20.1
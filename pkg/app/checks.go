@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/cluster"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+const (
+	// DefaultCheckTimeout is how long each check is retried for before it fails
+	DefaultCheckTimeout = 5 * time.Minute
+)
+
+var checkRetryInterval = 10 * time.Second
+
+// checkRequestTimeout limits each attempt of a check, so that a request to the app or the
+// cluster api that hangs is retried instead of using the whole check timeout
+var checkRequestTimeout = 30 * time.Second
+
+var checkHTTPClient = &http.Client{
+	Timeout: checkRequestTimeout,
+}
+
+// validateApplicationChecks returns an error if any check does not have exactly one check set
+func validateApplicationChecks(checks []types.ApplicationCheck) error {
+	for i, check := range checks {
+		count := 0
+		if check.HTTPGet != nil {
+			count++
+		}
+		if check.ReadyEndpoints != nil {
+			count++
+		}
+		if check.NoCrashLoopBackOff != nil {
+			count++
+		}
+
+		if count != 1 {
+			return errors.Errorf("check %d (%s) must have exactly one of httpGet, readyEndpoints or noCrashLoopBackOff", i, checkName(check))
+		}
+	}
+
+	return nil
+}
+
+// runApplicationChecks will evaluate each check on the cluster, retrying until it passes
+// or the timeout is reached, and record the results on the cluster
+func runApplicationChecks(ctx context.Context, c *types.ClusterConfig, applicationSpec types.ApplicationSpec) error {
+	timeout := DefaultCheckTimeout
+	if applicationSpec.CheckTimeout != "" {
+		d, err := time.ParseDuration(applicationSpec.CheckTimeout)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse check timeout")
+		}
+		timeout = d
+	}
+
+	results := []types.CheckResult{}
+	failed := 0
+	for _, check := range applicationSpec.Checks {
+		result := types.CheckResult{
+			Check: checkName(check),
+			State: types.CheckStatePass,
+		}
+
+		if err := retryCheck(ctx, c, check, timeout); err != nil {
+			result.State = types.CheckStateFail
+			result.Message = err.Error()
+			failed++
+		}
+
+		results = append(results, result)
+	}
+	c.CheckResults = results
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d checks failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// retryCheck returns the error from the last attempt when the check does not pass before the timeout
+func retryCheck(ctx context.Context, c *types.ClusterConfig, check types.ApplicationCheck, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := evaluateCheck(ctx, c, check)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(checkRetryInterval).After(deadline) {
+			return err
+		}
+
+		select {
+		case <-time.After(checkRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func evaluateCheck(ctx context.Context, c *types.ClusterConfig, check types.ApplicationCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, checkRequestTimeout)
+	defer cancel()
+
+	switch {
+	case check.HTTPGet != nil:
+		return evaluateHTTPGetCheck(ctx, c, check.HTTPGet)
+	case check.ReadyEndpoints != nil:
+		return evaluateReadyEndpointsCheck(ctx, c, check.ReadyEndpoints)
+	case check.NoCrashLoopBackOff != nil:
+		return evaluateNoCrashLoopBackOffCheck(ctx, c, check.NoCrashLoopBackOff)
+	}
+
+	return errors.New("no check specified")
+}
+
+func evaluateHTTPGetCheck(ctx context.Context, c *types.ClusterConfig, httpGet *types.HTTPGetCheck) error {
+	var statusCode int
+	var body []byte
+
+	if httpGet.URL != "" {
+		url, err := RenderClusterTemplate(c, httpGet.URL)
+		if err != nil {
+			return errors.Wrap(err, "failed to render url")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to create request")
+		}
+		resp, err := checkHTTPClient.Do(req)
+		if err != nil {
+			return errors.Wrap(err, "failed to get url")
+		}
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "failed to read response body")
+		}
+		statusCode, body = resp.StatusCode, b
+	} else {
+		code, b, err := cluster.ProxyServiceGetWithStatus(ctx, c, httpGet.Namespace, httpGet.Service, strings.TrimPrefix(httpGet.Path, "/"))
+		if err != nil {
+			return errors.Wrap(err, "failed to get service")
+		}
+		statusCode, body = code, b
+	}
+
+	expectedStatus := httpGet.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if statusCode != expectedStatus {
+		return errors.Errorf("expected status %d, got %d", expectedStatus, statusCode)
+	}
+
+	if httpGet.BodyRegex != "" {
+		re, err := regexp.Compile(httpGet.BodyRegex)
+		if err != nil {
+			return errors.Wrap(err, "failed to compile body regex")
+		}
+		if !re.Match(body) {
+			return errors.Errorf("body does not match %q", httpGet.BodyRegex)
+		}
+	}
+
+	return nil
+}
+
+func evaluateReadyEndpointsCheck(ctx context.Context, c *types.ClusterConfig, readyEndpoints *types.ReadyEndpointsCheck) error {
+	count, err := cluster.GetReadyEndpointsCount(ctx, c, readyEndpoints.Namespace, readyEndpoints.Service)
+	if err != nil {
+		return errors.Wrap(err, "failed to get ready endpoints")
+	}
+
+	if count < readyEndpoints.Count {
+		return errors.Errorf("expected %d ready endpoints, got %d", readyEndpoints.Count, count)
+	}
+
+	return nil
+}
+
+func evaluateNoCrashLoopBackOffCheck(ctx context.Context, c *types.ClusterConfig, noCrashLoopBackOff *types.NoCrashLoopBackOffCheck) error {
+	pods, err := cluster.ListCrashLoopingPods(ctx, c, noCrashLoopBackOff.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pods")
+	}
+
+	if len(pods) > 0 {
+		return errors.Errorf("pods in CrashLoopBackOff: %s", strings.Join(pods, ", "))
+	}
+
+	return nil
+}
+
+// checkName returns the name of the check, or a description of it when it is not named
+func checkName(check types.ApplicationCheck) string {
+	if check.Name != "" {
+		return check.Name
+	}
+
+	switch {
+	case check.HTTPGet != nil && check.HTTPGet.URL != "":
+		return fmt.Sprintf("GET %s", check.HTTPGet.URL)
+	case check.HTTPGet != nil:
+		return fmt.Sprintf("GET %s/%s%s", check.HTTPGet.Namespace, check.HTTPGet.Service, check.HTTPGet.Path)
+	case check.ReadyEndpoints != nil:
+		return fmt.Sprintf("%s/%s has %d ready endpoints", check.ReadyEndpoints.Namespace, check.ReadyEndpoints.Service, check.ReadyEndpoints.Count)
+	case check.NoCrashLoopBackOff != nil:
+		return fmt.Sprintf("no pods in CrashLoopBackOff in %s", check.NoCrashLoopBackOff.Namespace)
+	}
+
+	return "unknown"
+}
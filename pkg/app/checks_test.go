@@ -0,0 +1,225 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluateHTTPGetCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cluster-a/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ok","version":"1.2.3"}`)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	clusterConfig := &types.ClusterConfig{
+		Name: "cluster-a",
+	}
+
+	tests := []struct {
+		name        string
+		httpGet     *types.HTTPGetCheck
+		expectError bool
+	}{
+		{
+			name: "templated url with body regex",
+			httpGet: &types.HTTPGetCheck{
+				URL:       server.URL + "/{{ .Name }}/healthz",
+				BodyRegex: `"status":\s*"ok"`,
+			},
+		},
+		{
+			name: "body does not match",
+			httpGet: &types.HTTPGetCheck{
+				URL:       server.URL + "/{{ .Name }}/healthz",
+				BodyRegex: `"status":\s*"degraded"`,
+			},
+			expectError: true,
+		},
+		{
+			name: "unexpected status",
+			httpGet: &types.HTTPGetCheck{
+				URL: server.URL + "/missing",
+			},
+			expectError: true,
+		},
+		{
+			name: "expected status",
+			httpGet: &types.HTTPGetCheck{
+				URL:            server.URL + "/missing",
+				ExpectedStatus: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := evaluateHTTPGetCheck(context.Background(), clusterConfig, test.httpGet)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_runApplicationChecks(t *testing.T) {
+	defaultInterval := checkRetryInterval
+	checkRetryInterval = 10 * time.Millisecond
+	defer func() {
+		checkRetryInterval = defaultInterval
+	}()
+
+	// the app becomes healthy after a few requests
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	clusterConfig := &types.ClusterConfig{
+		Name: "cluster-a",
+	}
+
+	err := runApplicationChecks(context.Background(), clusterConfig, types.ApplicationSpec{
+		CheckTimeout: "1s",
+		Checks: []types.ApplicationCheck{
+			{
+				Name: "healthz",
+				HTTPGet: &types.HTTPGetCheck{
+					URL: server.URL,
+				},
+			},
+			{
+				HTTPGet: &types.HTTPGetCheck{
+					URL:       server.URL,
+					BodyRegex: "never",
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+
+	require.Len(t, clusterConfig.CheckResults, 2)
+	assert.Equal(t, types.CheckResult{Check: "healthz", State: types.CheckStatePass}, clusterConfig.CheckResults[0])
+	assert.Equal(t, fmt.Sprintf("GET %s", server.URL), clusterConfig.CheckResults[1].Check)
+	assert.Equal(t, types.CheckStateFail, clusterConfig.CheckResults[1].State)
+	assert.Equal(t, `body does not match "never"`, clusterConfig.CheckResults[1].Message)
+}
+
+func Test_runApplicationChecksCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	clusterConfig := &types.ClusterConfig{
+		Name: "cluster-a",
+	}
+
+	start := time.Now()
+	err := runApplicationChecks(ctx, clusterConfig, types.ApplicationSpec{
+		Checks: []types.ApplicationCheck{
+			{
+				HTTPGet: &types.HTTPGetCheck{
+					URL: server.URL,
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+	assert.True(t, time.Since(start) < checkRetryInterval)
+}
+
+func Test_evaluateCheckTimeout(t *testing.T) {
+	defaultTimeout := checkRequestTimeout
+	checkRequestTimeout = 10 * time.Millisecond
+	defer func() {
+		checkRequestTimeout = defaultTimeout
+	}()
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	clusterConfig := &types.ClusterConfig{
+		Name: "cluster-a",
+	}
+
+	start := time.Now()
+	err := evaluateCheck(context.Background(), clusterConfig, types.ApplicationCheck{
+		HTTPGet: &types.HTTPGetCheck{
+			URL: server.URL,
+		},
+	})
+	require.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func Test_validateApplicationChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		checks      []types.ApplicationCheck
+		expectError bool
+	}{
+		{
+			name: "one check each",
+			checks: []types.ApplicationCheck{
+				{HTTPGet: &types.HTTPGetCheck{URL: "http://example.com"}},
+				{NoCrashLoopBackOff: &types.NoCrashLoopBackOffCheck{Namespace: "my-app"}},
+			},
+		},
+		{
+			name: "no check",
+			checks: []types.ApplicationCheck{
+				{Name: "empty"},
+			},
+			expectError: true,
+		},
+		{
+			name: "two checks",
+			checks: []types.ApplicationCheck{
+				{
+					HTTPGet:            &types.HTTPGetCheck{URL: "http://example.com"},
+					NoCrashLoopBackOff: &types.NoCrashLoopBackOffCheck{Namespace: "my-app"},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateApplicationChecks(test.checks)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
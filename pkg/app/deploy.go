@@ -1,69 +1,183 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"reflect"
+	"os"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/logger"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 )
 
-func Deploy(g *types.GridConfig, a *types.Application) error {
-	completed := map[int]bool{}
-	completedChans := make([]chan string, len(g.ClusterConfigs))
-	for i := range g.ClusterConfigs {
-		completedChans[i] = make(chan string)
-		completed[i] = false
+type DeployOptions struct {
+	// SupportBundleDir will collect a support bundle into this dir from each
+	// cluster that the app fails to deploy to, when set
+	SupportBundleDir string
+	// Parallelism is the maximum number of clusters to deploy to at once, or 0 for no limit
+	Parallelism int
+	// LoggerSpec is the logger for the progress on each cluster, the terminal when not set
+	LoggerSpec types.LoggerSpec
+}
+
+// DefaultReadyTimeout is how long deploy waits for the application to be ready on each cluster
+const DefaultReadyTimeout = 10 * time.Minute
+
+type ClusterDeployResult struct {
+	ClusterName       string
+	StartedAt         time.Time
+	FinishedAt        time.Time
+	Error             string
+	Steps             []types.StepResult
+	SupportBundlePath string
+}
+
+// runStep will run fn and record it as a step in the result. message is logged with the step
+// when it starts, and the error when it fails
+func (r *ClusterDeployResult) runStep(ctx context.Context, log logger.Logger, name string, message string, fn func() error) error {
+	watch.Step(ctx, name)
+	log = log.WithFields(logger.Fields{logger.FieldStep: name})
+	log.Info(message)
+
+	step := types.StepResult{
+		Name:      name,
+		StartedAt: time.Now(),
+	}
+	_, span := tracing.Start(ctx, name, tracing.StepKey.String(name))
+	err := fn()
+	tracing.End(span, err)
+	step.FinishedAt = time.Now()
+	if err != nil {
+		step.Error = err.Error()
+		log.Error(err)
+	}
+	r.Steps = append(r.Steps, step)
+
+	return err
+}
+
+// Deploy will deploy the application to every cluster in the grid, and return the
+// result of the deploy on each cluster. When the app fails to deploy to any cluster,
+// the results are returned with an *orchestrator.Error
+func Deploy(ctx context.Context, g *types.GridConfig, a *types.Application, opts DeployOptions) (_ []*ClusterDeployResult, err error) {
+	ctx, span := tracing.Start(ctx, "deploy", tracing.GridKey.String(g.Name), tracing.AppKey.String(a.Name))
+	defer func() { tracing.End(span, err) }()
+
+	if a.Spec.KOTSApplicationSpec == nil {
+		return nil, errors.New("only kots applications can be deployed")
 	}
+	if err := validateApplicationChecks(a.Spec.Checks); err != nil {
+		return nil, errors.Wrap(err, "invalid checks")
+	}
+
+	// the license is the same for every cluster, so only load it once
+	licenseFilePath, license, err := loadKOTSLicense(a.Spec.KOTSApplicationSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load license")
+	}
+	defer os.RemoveAll(licenseFilePath)
+	appSlug := license.Spec.AppSlug
+	span.SetAttributes(tracing.ChannelKey.String(license.Spec.ChannelName))
+
+	ctx, log := logger.NewOperationLogger(ctx, opts.LoggerSpec, logger.Fields{
+		logger.FieldOperation: "Deploy",
+		logger.FieldGrid:      g.Name,
+		logger.FieldApp:       a.Name,
+		logger.FieldChannel:   license.Spec.ChannelName,
+	})
+	defer log.Finish()
 
-	finished := make(chan bool)
-	go func() {
-		cases := make([]reflect.SelectCase, len(completedChans))
-		for i, ch := range completedChans {
-			cases[i] = reflect.SelectCase{
-				Dir:  reflect.SelectRecv,
-				Chan: reflect.ValueOf(ch),
-			}
+	// clusters that are pending or failed have nothing to deploy to
+	clusters := g.ReadyClusters()
+	results := make([]*ClusterDeployResult, len(clusters))
+	tasks := []orchestrator.Task{}
+	for i, c := range clusters {
+		result := &ClusterDeployResult{
+			ClusterName: c.Name,
 		}
+		results[i] = result
 
-		for {
-			i, completedErr, ok := reflect.Select(cases)
-			if ok {
-				if completedErr.String() != "" {
-					fmt.Printf("cluster %#v failed with error: %s\n", g.ClusterConfigs[i], completedErr.String())
-				}
-
-				completed[i] = true
-			}
-
-			allCompleted := true
-			for _, v := range completed {
-				if !v {
-					allCompleted = false
-				}
-			}
-
-			if allCompleted {
-				finished <- true
-				return
-			}
+		c := c
+		tasks = append(tasks, orchestrator.Task{
+			Cluster: c.Name,
+			Run: func(ctx context.Context) error {
+				return deployToCluster(ctx, log.WithCluster(c.Name), g, c, a, licenseFilePath, appSlug, opts, result)
+			},
+		})
+	}
+
+	taskResults, err := orchestrator.Run(ctx, tasks, orchestrator.Options{
+		Parallelism: opts.Parallelism,
+	})
+
+	// clusters that were not deployed to because the deploy was cancelled have no error yet
+	for i, taskResult := range taskResults {
+		if taskResult.Err != nil && results[i].Error == "" {
+			results[i].Error = taskResult.Err.Error()
 		}
-	}()
-
-	// deploy the app
-	for i, c := range g.ClusterConfigs {
-		if a.Spec.KOTSApplicationSpec != nil {
-			go func() {
-				err := deployKOTSApplication(c, a.Spec.KOTSApplicationSpec)
-				if err != nil {
-					completedChans[i] <- err.Error()
-				} else {
-					completedChans[i] <- ""
-				}
-			}()
+	}
+
+	return results, err
+}
+
+func deployToCluster(ctx context.Context, log logger.Logger, g *types.GridConfig, c *types.ClusterConfig, a *types.Application, pathToLicense string, appSlug string, opts DeployOptions, result *ClusterDeployResult) error {
+	result.StartedAt = time.Now()
+	err := deployKOTSApplicationSteps(ctx, c, a.Spec.KOTSApplicationSpec, pathToLicense, appSlug, result, log)
+	if err == nil && len(a.Spec.Checks) > 0 {
+		err = result.runStep(ctx, log, types.StepChecks, "Running application checks", func() error {
+			return runApplicationChecks(ctx, c, a.Spec)
+		})
+	}
+	result.FinishedAt = time.Now()
+	if err == nil {
+		c.SetCondition(types.ClusterConditionAppDeployed, types.ConditionStatusTrue, "")
+		c.SetDeployedApplication(a.Name)
+		log.Info("Deployed application %s", a.Name)
+		return nil
+	}
+
+	c.SetCondition(types.ClusterConditionAppDeployed, types.ConditionStatusFalse, err.Error())
+	result.Error = err.Error()
+	if opts.SupportBundleDir != "" {
+		bundlePath, bundleErr := collectSupportBundle(ctx, g.Name, c, appSlug, SupportBundleOptions{
+			Application: a,
+			OutputDir:   opts.SupportBundleDir,
+		})
+		if bundleErr != nil {
+			log.Error(errors.Wrap(bundleErr, "failed to collect support bundle"))
+		} else {
+			log.Info("Collected support bundle to %s", bundlePath)
+			result.SupportBundlePath = bundlePath
 		}
 	}
 
-	<-finished
+	return err
+}
+
+// deployKOTSApplicationSteps will install the application, collect preflights and wait
+// for the application to be ready, recording each as a step
+func deployKOTSApplicationSteps(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec, pathToLicense string, appSlug string, result *ClusterDeployResult, log logger.Logger) error {
+	err := result.runStep(ctx, log, types.StepDeploy, fmt.Sprintf("Installing application %s", appSlug), func() error {
+		return deployKOTSApplication(ctx, c, kotsAppSpec, pathToLicense, appSlug)
+	})
+	if err != nil {
+		return err
+	}
+
+	if kotsAppSpec.SkipPreflights == nil || !*kotsAppSpec.SkipPreflights {
+		err := result.runStep(ctx, log, types.StepPreflights, "Collecting preflight results", func() error {
+			return collectKOTSPreflights(ctx, c, kotsAppSpec, appSlug, log)
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return result.runStep(ctx, log, types.StepReadiness, "Waiting for application to be ready", func() error {
+		return waitForKOTSApplicationReady(ctx, c, kotsAppSpec, appSlug, DefaultReadyTimeout)
+	})
 }
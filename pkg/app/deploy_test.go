@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeployFailedCluster(t *testing.T) {
+	g := &types.GridConfig{
+		Name: "grid",
+		ClusterConfigs: []*types.ClusterConfig{
+			{
+				Name: "failed",
+				Status: &types.ClusterStatus{
+					Phase:   types.ClusterPhaseFailed,
+					Message: "nodes did not join",
+				},
+			},
+			{
+				Name: "pending",
+				Status: &types.ClusterStatus{
+					Phase: types.ClusterPhasePending,
+				},
+			},
+		},
+	}
+	a := &types.Application{
+		Spec: types.ApplicationSpec{
+			KOTSApplicationSpec: &types.KOTSApplicationSpec{
+				App: "my-app",
+				License: &types.ValueOrValueFrom{
+					Value: `apiVersion: kots.io/v1beta1
+kind: License
+spec:
+  appSlug: my-app
+  channelName: Stable
+`,
+				},
+			},
+		},
+	}
+
+	// clusters without a kubeconfig are not deployed to
+	results, err := Deploy(context.Background(), g, a, DeployOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Nil(t, g.ClusterConfigs[0].GetCondition(types.ClusterConditionAppDeployed))
+}
@@ -1,26 +1,18 @@
 package app
 
 import (
-	"archive/tar"
 	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
-	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"strings"
 	"time"
 
-	"github.com/gosimple/slug"
 	"github.com/pkg/errors"
-	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
-	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 )
 
 const (
@@ -46,35 +38,13 @@ type ResourceState struct {
 	State     string `json:"state"`
 }
 
-func getAppSlug(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec) (string, error) {
-	// this is _really_ brittle
-	// the KOTS admin console doesn't give us a way to predict the app slug
-	// so we've copied the same logic that KOTS uses
-	// and it's sort of ok, but definitely is going to screw up
-
-	// let's make kots return a list of apps?
-	licenseFilePath, err := downloadKOTSLicense(kotsAppSpec.App, kotsAppSpec.LicenseID)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to download license")
-	}
-	defer os.RemoveAll(licenseFilePath)
-
-	license, err := kotsutil.LoadLicenseFromPath(licenseFilePath)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to load license")
-	}
-
-	desiredAppName := strings.Replace(license.Spec.AppSlug, "-", " ", 0)
-	titleForSlug := strings.Replace(desiredAppName, ".", "-", 0)
-	return slug.Make(titleForSlug), nil
-}
-
 // isApplicationReady will return
-//  bool1: is the application ready
-func isApplicationReady(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec) (bool, error) {
+//
+//	bool1: is the application ready
+func isApplicationReady(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec, appSlug string) (bool, error) {
 	// right now, we just check the status informers
 
-	pathToKOTSBinary, err := downloadKOTSBinary(kotsAppSpec.Version)
+	pathToKOTSBinary, err := getKOTSBinary(kotsAppSpec)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to get kots binary")
 	}
@@ -88,15 +58,7 @@ func isApplicationReady(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicati
 		return false, errors.Wrap(err, "failed to create kubeconfig")
 	}
 
-	namespace := kotsAppSpec.Namespace
-	if namespace == "" {
-		namespace = kotsAppSpec.App
-	}
-
-	appSlug, err := getAppSlug(c, kotsAppSpec)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to get app slug")
-	}
+	namespace := kotsNamespace(kotsAppSpec)
 
 	args := []string{
 		"--namespace", namespace,
@@ -109,7 +71,7 @@ func isApplicationReady(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicati
 		appSlug,
 	}
 	allArgs = append(allArgs, args...)
-	cmd := exec.Command(pathToKOTSBinary, allArgs...)
+	cmd := exec.CommandContext(ctx, pathToKOTSBinary, allArgs...)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -125,8 +87,10 @@ func isApplicationReady(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicati
 	select {
 	case <-timeout:
 		cmd.Process.Kill()
-		fmt.Printf("timedout waiting for app ready.  received std out: %s\n", stdout.String())
+		watch.Printf(ctx, "timed out waiting for app ready.  received std out: %s\n", stdout.String())
 		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
 	case err := <-done:
 		if err != nil {
 			return false, errors.Wrap(err, "failed to run kots")
@@ -141,19 +105,13 @@ func isApplicationReady(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicati
 	}
 }
 
-func deployKOTSApplication(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec) error {
+func deployKOTSApplication(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec, pathToLicense string, appSlug string) error {
 	// ensure we have the right version of KOTS
-	pathToKOTSBinary, err := downloadKOTSBinary(kotsAppSpec.Version)
+	pathToKOTSBinary, err := getKOTSBinary(kotsAppSpec)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kots binary")
 	}
 
-	pathToLicense, err := downloadKOTSLicense(kotsAppSpec.App, kotsAppSpec.LicenseID)
-	if err != nil {
-		return errors.Wrap(err, "failed to get license")
-	}
-	defer os.RemoveAll(pathToLicense)
-
 	kubeconfigFile, err := ioutil.TempFile("", "kots")
 	if err != nil {
 		return errors.Wrap(err, "failed to create temp file")
@@ -163,34 +121,27 @@ func deployKOTSApplication(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplic
 		return errors.Wrap(err, "failed to create kubeconfig")
 	}
 
-	namespace := kotsAppSpec.Namespace
-	if namespace == "" {
-		namespace = kotsAppSpec.App
+	namespace := kotsNamespace(kotsAppSpec)
+
+	adminConsoleArgs, adminConsoleConfig, err := kotsAdminConsoleArgs(kotsAppSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to get admin console options")
 	}
 
 	args := []string{
 		"--namespace", namespace,
 		"--license-file", pathToLicense,
-		"--shared-password", "password",
 		"--port-forward=false",
 		"--kubeconfig", kubeconfigFile.Name(),
 	}
+	args = append(args, adminConsoleArgs...)
 
-	if kotsAppSpec.ConfigValues != nil {
-		configValues := kotsv1beta1.ConfigValues{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "kots.io/v1beta1",
-				Kind:       "ConfigValues",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "automated-config-values",
-			},
-			Spec: *kotsAppSpec.ConfigValues,
-		}
-		kotsKinds := kotsutil.KotsKinds{
-			ConfigValues: &configValues,
-		}
-		b, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
+	configValues, err := RenderKOTSConfigValues(c, kotsAppSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to render config values")
+	}
+	if configValues != nil {
+		b, err := MarshalKOTSConfigValues(configValues)
 		if err != nil {
 			return errors.Wrap(err, "failed to marshal config values")
 		}
@@ -199,13 +150,23 @@ func deployKOTSApplication(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplic
 			return errors.Wrap(err, "failed to create temp file")
 		}
 		defer os.RemoveAll(configValuesFile.Name())
-		if err := ioutil.WriteFile(configValuesFile.Name(), []byte(b), 0644); err != nil {
+		if err := ioutil.WriteFile(configValuesFile.Name(), b, 0644); err != nil {
 			return errors.Wrap(err, "failed to write config values to file")
 		}
 		args = append(args, "--config-values")
 		args = append(args, configValuesFile.Name())
 	}
 
+	env := []string{}
+	if kotsAppSpec.Airgap != nil {
+		airgapArgs, airgapEnv, err := prepareKOTSAirgapInstall(ctx, pathToKOTSBinary, kubeconfigFile.Name(), kotsAppSpec.Airgap)
+		if err != nil {
+			return errors.Wrap(err, "failed to prepare airgap install")
+		}
+		args = append(args, airgapArgs...)
+		env = append(env, airgapEnv...)
+	}
+
 	if kotsAppSpec.SkipPreflights != nil && *kotsAppSpec.SkipPreflights {
 		args = append(args, "--skip-preflights")
 	}
@@ -216,7 +177,8 @@ func deployKOTSApplication(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplic
 	}
 	allArgs = append(allArgs, args...)
 
-	cmd := exec.Command(pathToKOTSBinary, allArgs...)
+	cmd := exec.CommandContext(ctx, pathToKOTSBinary, allArgs...)
+	cmd.Env = append(os.Environ(), env...)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -232,118 +194,42 @@ func deployKOTSApplication(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplic
 	select {
 	case <-timeout:
 		cmd.Process.Kill()
-		fmt.Printf("timeoud out deploying app.  received std out: %s\n", stdout.String())
+		watch.Printf(ctx, "timed out deploying app.  received std out: %s\n", stdout.String())
+		return errors.New("timed out waiting for kots install")
 	case err := <-done:
 		if err != nil {
 			return errors.Wrap(err, "failed to run kots")
 		}
 
-		fmt.Printf("%s\n", stdout.String())
+		watch.Printf(ctx, "%s\n", stdout.String())
 	}
 
+	// the admin console is only recorded once it's installed with this password
+	c.AdminConsole = adminConsoleConfig
+
 	return nil
 }
 
-// the caller is responsible for deleting the file
-func downloadKOTSLicense(appSlug string, licenseID string) (string, error) {
-	url := fmt.Sprintf("https://replicated.app/license/%s", appSlug)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create new request")
-	}
-
-	req.SetBasicAuth(licenseID, licenseID)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to execute request")
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.Errorf("unexpected status code downloading license: %d", resp.StatusCode)
-	}
-
-	archiveFile, err := ioutil.TempFile("", "kots")
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create temp archive file")
-	}
+// undeployKOTSApplication removes the namespace that the application and
+// admin console were installed into
+func undeployKOTSApplication(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec) error {
+	namespace := kotsNamespace(kotsAppSpec)
 
-	_, err = io.Copy(archiveFile, resp.Body)
-	if err != nil {
-		return "", errors.Wrap(err, "Failed to copy file")
+	if err := kubectl.DeleteNamespace(ctx, c, namespace); err != nil {
+		return errors.Wrapf(err, "failed to delete namespace %s", namespace)
 	}
 
-	archiveFile.Close()
-	return archiveFile.Name(), nil
+	return nil
 }
 
-func downloadKOTSBinary(version string) (string, error) {
-	if version == "" {
-		version = DefaultKOTSVersion
-	}
-
-	url := fmt.Sprintf("https://github.com/replicatedhq/kots/releases/download/v%s/kots_linux_amd64.tar.gz", version)
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to http get kots")
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.Errorf("failed to download, unexpected status code %d", resp.StatusCode)
-	}
-
-	archiveFile, err := ioutil.TempFile("", "kots")
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create temp archive file")
-	}
-	defer os.RemoveAll(archiveFile.Name())
-
-	_, err = io.Copy(archiveFile, resp.Body)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to save archive file")
+func kotsNamespace(kotsAppSpec *types.KOTSApplicationSpec) string {
+	if kotsAppSpec.AdminConsole != nil && kotsAppSpec.AdminConsole.Namespace != "" {
+		return kotsAppSpec.AdminConsole.Namespace
 	}
 
-	if _, err := archiveFile.Seek(0, 0); err != nil {
-		return "", errors.Wrap(err, "failed to seek")
-	}
-
-	gzf, err := gzip.NewReader(archiveFile)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create gzip reader")
-	}
-
-	tarReader := tar.NewReader(gzf)
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-
-		if err != nil {
-			return "", errors.Wrap(err, "failed to read next file")
-		}
-
-		name := header.Name
-		if name == "kots" {
-			tmpDir, err := ioutil.TempDir("", "kots")
-			if err != nil {
-				return "", errors.Wrap(err, "failed to create tmp dir")
-			}
-
-			binaryPath := filepath.Join(tmpDir, "kots")
-			f, err := os.Create(binaryPath)
-			if err != nil {
-				return "", errors.Wrap(err, "failed to os create file")
-			}
-			defer f.Close()
-			if _, err := io.Copy(f, tarReader); err != nil {
-				return "", errors.Wrap(err, "failed to copy kots binary")
-			}
-			if err := os.Chmod(binaryPath, 0777); err != nil {
-				return "", errors.Wrap(err, "faild to chmod")
-			}
-
-			return binaryPath, nil
-		}
+	if kotsAppSpec.Namespace != "" {
+		return kotsAppSpec.Namespace
 	}
 
-	return "", errors.New("kots binary not found in release")
+	return kotsAppSpec.App
 }
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// kotsAdminConsoleArgs returns the kots install args for the admin console, and the
+// admin console config that should be recorded for the cluster
+func kotsAdminConsoleArgs(kotsAppSpec *types.KOTSApplicationSpec) ([]string, *types.AdminConsoleConfig, error) {
+	adminConsoleConfig := &types.AdminConsoleConfig{
+		Namespace: kotsNamespace(kotsAppSpec),
+	}
+
+	adminConsoleSpec := kotsAppSpec.AdminConsole
+	if adminConsoleSpec == nil {
+		adminConsoleSpec = &types.KOTSAdminConsoleSpec{}
+	}
+
+	var sharedPassword string
+	if adminConsoleSpec.SharedPassword != nil {
+		password, err := adminConsoleSpec.SharedPassword.String()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to read shared password")
+		}
+		sharedPassword = password
+	} else {
+		password, err := generateSharedPassword()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to generate shared password")
+		}
+		sharedPassword = password
+		adminConsoleConfig.SharedPassword = password
+	}
+
+	args := []string{
+		"--shared-password", sharedPassword,
+	}
+
+	if adminConsoleSpec.WaitDuration != "" {
+		if _, err := time.ParseDuration(adminConsoleSpec.WaitDuration); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse wait duration")
+		}
+		args = append(args, "--wait-duration", adminConsoleSpec.WaitDuration)
+	}
+
+	return args, adminConsoleConfig, nil
+}
+
+func generateSharedPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to read random bytes")
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+type kotsRegistry struct {
+	hostname  string
+	namespace string
+	username  string
+	password  string
+}
+
+// prepareKOTSAirgapInstall will push the admin console images to the private registry, if
+// a kotsadm bundle was provided, and return the additional args and environment to pass to
+// kots install
+func prepareKOTSAirgapInstall(ctx context.Context, pathToKOTSBinary string, kubeconfigFile string, airgapSpec *types.KOTSAirgapSpec) ([]string, []string, error) {
+	if _, err := os.Stat(airgapSpec.BundlePath); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to stat airgap bundle")
+	}
+
+	registry, err := resolveKOTSRegistry(airgapSpec.Registry)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to resolve registry")
+	}
+
+	if airgapSpec.KotsadmBundlePath != "" {
+		if err := pushKOTSAdminConsoleImages(ctx, pathToKOTSBinary, kubeconfigFile, airgapSpec.KotsadmBundlePath, registry); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to push admin console images")
+		}
+	}
+
+	args := []string{
+		"--airgap-bundle", airgapSpec.BundlePath,
+		"--kotsadm-registry", registry.hostname,
+		"--kotsadm-namespace", registry.namespace,
+	}
+
+	return args, registry.env(), nil
+}
+
+func resolveKOTSRegistry(registrySpec types.KOTSRegistrySpec) (*kotsRegistry, error) {
+	hostname, err := registrySpec.Hostname.String()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read registry hostname")
+	}
+	namespace, err := registrySpec.Namespace.String()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read registry namespace")
+	}
+
+	registry := &kotsRegistry{
+		hostname:  hostname,
+		namespace: namespace,
+	}
+
+	// a registry without credentials is used anonymously, such as a local registry:2
+	if !registrySpec.Username.IsEmpty() {
+		registry.username, err = registrySpec.Username.String()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read registry username")
+		}
+	}
+	if !registrySpec.Password.IsEmpty() {
+		registry.password, err = registrySpec.Password.String()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read registry password")
+		}
+	}
+
+	return registry, nil
+}
+
+// env returns the credentials as the environment variables that kots reads its
+// --registry-username and --registry-password flags from, so that the password isn't
+// visible in the process list
+func (r *kotsRegistry) env() []string {
+	env := []string{}
+	if r.username != "" {
+		env = append(env, fmt.Sprintf("KOTS_REGISTRY_USERNAME=%s", r.username))
+	}
+	if r.password != "" {
+		env = append(env, fmt.Sprintf("KOTS_REGISTRY_PASSWORD=%s", r.password))
+	}
+
+	return env
+}
+
+func pushKOTSAdminConsoleImages(ctx context.Context, pathToKOTSBinary string, kubeconfigFile string, kotsadmBundlePath string, registry *kotsRegistry) error {
+	allArgs := []string{
+		"admin-console", "push-images",
+		kotsadmBundlePath,
+		fmt.Sprintf("%s/%s", registry.hostname, registry.namespace),
+		"--kubeconfig", kubeconfigFile,
+	}
+
+	cmd := exec.CommandContext(ctx, pathToKOTSBinary, allArgs...)
+	cmd.Env = append(os.Environ(), registry.env()...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "failed to run kots: %s", output)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveKOTSRegistry(t *testing.T) {
+	tests := []struct {
+		name         string
+		registrySpec types.KOTSRegistrySpec
+		expectedEnv  []string
+	}{
+		{
+			name: "anonymous local registry",
+			registrySpec: types.KOTSRegistrySpec{
+				Hostname:  types.ValueOrValueFrom{Value: "localhost:5000"},
+				Namespace: types.ValueOrValueFrom{Value: "my-app"},
+			},
+			expectedEnv: []string{},
+		},
+		{
+			name: "registry with credentials",
+			registrySpec: types.KOTSRegistrySpec{
+				Hostname:  types.ValueOrValueFrom{Value: "registry.example.com"},
+				Namespace: types.ValueOrValueFrom{Value: "my-app"},
+				Username:  types.ValueOrValueFrom{Value: "user"},
+				Password:  types.ValueOrValueFrom{Value: "secret"},
+			},
+			expectedEnv: []string{
+				"KOTS_REGISTRY_USERNAME=user",
+				"KOTS_REGISTRY_PASSWORD=secret",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry, err := resolveKOTSRegistry(test.registrySpec)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.registrySpec.Hostname.Value, registry.hostname)
+			assert.Equal(t, test.registrySpec.Namespace.Value, registry.namespace)
+			assert.Equal(t, test.expectedEnv, registry.env())
+		})
+	}
+}
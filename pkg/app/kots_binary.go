@@ -0,0 +1,223 @@
+package app
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+const (
+	DefaultKOTSDownloadURL = "https://github.com/replicatedhq/kots/releases/download"
+)
+
+var (
+	kotsDownloadsLock sync.Mutex
+	kotsDownloads     = map[string]*kotsDownload{}
+)
+
+// kotsDownload tracks a single download of a kots version so that many clusters
+// deploying at the same time will share one download
+type kotsDownload struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+func getKOTSBinary(kotsAppSpec *types.KOTSApplicationSpec) (string, error) {
+	version := kotsAppSpec.Version
+	if version == "" {
+		version = DefaultKOTSVersion
+	}
+
+	downloadURL := kotsAppSpec.DownloadURL
+	if downloadURL == "" {
+		downloadURL = DefaultKOTSDownloadURL
+	}
+
+	cacheDir, err := kotsCacheDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cache dir")
+	}
+
+	return downloadKOTSBinary(downloadURL, cacheDir, version, runtime.GOOS, runtime.GOARCH)
+}
+
+func kotsCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get home dir")
+	}
+
+	return filepath.Join(home, ".grid", "cache", "kots"), nil
+}
+
+// downloadKOTSBinary will return the path to the kots binary for the version, downloading
+// and verifying it into the cache dir if it's not already there
+func downloadKOTSBinary(downloadURL string, cacheDir string, version string, goos string, goarch string) (string, error) {
+	key := filepath.Join(cacheDir, version, fmt.Sprintf("%s_%s", goos, goarch))
+
+	kotsDownloadsLock.Lock()
+	d, ok := kotsDownloads[key]
+	if !ok {
+		d = &kotsDownload{
+			done: make(chan struct{}),
+		}
+		kotsDownloads[key] = d
+	}
+	kotsDownloadsLock.Unlock()
+
+	if ok {
+		<-d.done
+		return d.path, d.err
+	}
+
+	d.path, d.err = fetchKOTSBinary(downloadURL, key, version, goos, goarch)
+	if d.err != nil {
+		// let the next caller retry
+		kotsDownloadsLock.Lock()
+		delete(kotsDownloads, key)
+		kotsDownloadsLock.Unlock()
+	}
+	close(d.done)
+
+	return d.path, d.err
+}
+
+func fetchKOTSBinary(downloadURL string, versionDir string, version string, goos string, goarch string) (string, error) {
+	binaryPath := filepath.Join(versionDir, "kots")
+	if _, err := os.Stat(binaryPath); err == nil {
+		return binaryPath, nil
+	}
+
+	assetName := fmt.Sprintf("kots_%s_%s.tar.gz", goos, goarch)
+	baseURL := fmt.Sprintf("%s/v%s", strings.TrimSuffix(downloadURL, "/"), version)
+
+	checksums, err := httpGetBytes(fmt.Sprintf("%s/kots_%s_checksums.txt", baseURL, version))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to download checksums")
+	}
+	expectedChecksum, err := findChecksum(checksums, assetName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find checksum")
+	}
+
+	archive, err := httpGetBytes(fmt.Sprintf("%s/%s", baseURL, assetName))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to download kots")
+	}
+
+	actualChecksum := fmt.Sprintf("%x", sha256.Sum256(archive))
+	if actualChecksum != expectedChecksum {
+		return "", errors.Errorf("checksum mismatch for %s: expected %s, got %s", assetName, expectedChecksum, actualChecksum)
+	}
+
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create cache dir")
+	}
+
+	if err := extractKOTSBinary(archive, binaryPath); err != nil {
+		return "", errors.Wrap(err, "failed to extract kots binary")
+	}
+
+	return binaryPath, nil
+}
+
+func httpGetBytes(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to http get")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	return b, nil
+}
+
+// findChecksum will return the sha256 for the asset from a checksums file
+// in the "<sha256>  <filename>" format
+func findChecksum(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		if fields[1] == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	return "", errors.Errorf("no checksum found for %s", assetName)
+}
+
+// extractKOTSBinary writes the kots binary from the archive to a temp file next to
+// binaryPath and renames it into place, so a partial extraction is never cached
+func extractKOTSBinary(archive []byte, binaryPath string) error {
+	gzf, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return errors.Wrap(err, "failed to create gzip reader")
+	}
+
+	tarReader := tar.NewReader(gzf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "failed to read next file")
+		}
+
+		if header.Name != "kots" {
+			continue
+		}
+
+		f, err := ioutil.TempFile(filepath.Dir(binaryPath), "kots")
+		if err != nil {
+			return errors.Wrap(err, "failed to create temp file")
+		}
+		defer os.RemoveAll(f.Name())
+
+		if _, err := io.Copy(f, tarReader); err != nil {
+			f.Close()
+			return errors.Wrap(err, "failed to copy kots binary")
+		}
+		if err := f.Close(); err != nil {
+			return errors.Wrap(err, "failed to close kots binary")
+		}
+		if err := os.Chmod(f.Name(), 0755); err != nil {
+			return errors.Wrap(err, "failed to chmod")
+		}
+		if err := os.Rename(f.Name(), binaryPath); err != nil {
+			return errors.Wrap(err, "failed to move kots binary into cache")
+		}
+
+		return nil
+	}
+
+	return errors.New("kots binary not found in release")
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_downloadKOTSBinary(t *testing.T) {
+	tests := []struct {
+		name        string
+		checksum    func(archive []byte) string
+		expectError bool
+	}{
+		{
+			name: "valid checksum",
+			checksum: func(archive []byte) string {
+				return fmt.Sprintf("%x", sha256.Sum256(archive))
+			},
+		},
+		{
+			name: "checksum mismatch",
+			checksum: func(archive []byte) string {
+				return fmt.Sprintf("%x", sha256.Sum256([]byte("not the archive")))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			archive := makeKOTSArchive(t, "#!/bin/sh\necho kots\n")
+
+			var archiveRequests int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v1.2.3/kots_1.2.3_checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "%s  kots_darwin_amd64.tar.gz\n", "0000")
+				fmt.Fprintf(w, "%s  kots_linux_arm64.tar.gz\n", test.checksum(archive))
+			})
+			mux.HandleFunc("/v1.2.3/kots_linux_arm64.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&archiveRequests, 1)
+				w.Write(archive)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			cacheDir, err := ioutil.TempDir("", "kots-cache")
+			req.NoError(err)
+			defer os.RemoveAll(cacheDir)
+
+			// many clusters deploying at once should share a single download
+			wg := sync.WaitGroup{}
+			paths := make([]string, 5)
+			errs := make([]error, 5)
+			for i := range paths {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					paths[i], errs[i] = downloadKOTSBinary(server.URL, cacheDir, "1.2.3", "linux", "arm64")
+				}(i)
+			}
+			wg.Wait()
+
+			if test.expectError {
+				for _, err := range errs {
+					req.Error(err)
+				}
+				_, err := os.Stat(filepath.Join(cacheDir, "1.2.3", "linux_arm64", "kots"))
+				req.True(os.IsNotExist(err))
+				return
+			}
+
+			for i := range paths {
+				req.NoError(errs[i])
+				assert.Equal(t, filepath.Join(cacheDir, "1.2.3", "linux_arm64", "kots"), paths[i])
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&archiveRequests))
+
+			contents, err := ioutil.ReadFile(paths[0])
+			req.NoError(err)
+			assert.Equal(t, "#!/bin/sh\necho kots\n", string(contents))
+		})
+	}
+}
+
+func makeKOTSArchive(t *testing.T, contents string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	err := tw.WriteHeader(&tar.Header{
+		Name: "kots",
+		Mode: 0755,
+		Size: int64(len(contents)),
+	})
+	require.NoError(t, err)
+	_, err = tw.Write([]byte(contents))
+	require.NoError(t, err)
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	return buf.Bytes()
+}
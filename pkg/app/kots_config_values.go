@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RenderKOTSConfigValues will return the config values to deploy to the cluster.
+// configValues are applied first, then configValuesFrom, then the clusterConfigValues
+// for this cluster. literal values are rendered as go templates with the cluster config
+// as the data, values read from valueFrom are used as is because they can hold secrets.
+// nil is returned if the app spec does not have any config values
+func RenderKOTSConfigValues(c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec) (*kotsv1beta1.ConfigValuesSpec, error) {
+	clusterValues := kotsAppSpec.ClusterConfigValues[c.Name]
+	if kotsAppSpec.ConfigValues == nil && len(kotsAppSpec.ConfigValuesFrom) == 0 && len(clusterValues) == 0 {
+		return nil, nil
+	}
+
+	rendered := &kotsv1beta1.ConfigValuesSpec{
+		Values: map[string]kotsv1beta1.ConfigValue{},
+	}
+
+	if kotsAppSpec.ConfigValues != nil {
+		for name, configValue := range kotsAppSpec.ConfigValues.Values {
+			var err error
+			if configValue.Default, err = RenderClusterTemplate(c, configValue.Default); err != nil {
+				return nil, errors.Wrapf(err, "failed to render default for %s", name)
+			}
+			if configValue.Value, err = RenderClusterTemplate(c, configValue.Value); err != nil {
+				return nil, errors.Wrapf(err, "failed to render value for %s", name)
+			}
+			if configValue.ValuePlaintext, err = RenderClusterTemplate(c, configValue.ValuePlaintext); err != nil {
+				return nil, errors.Wrapf(err, "failed to render plaintext value for %s", name)
+			}
+			rendered.Values[name] = configValue
+		}
+	}
+
+	for _, values := range []map[string]types.ValueOrValueFrom{kotsAppSpec.ConfigValuesFrom, clusterValues} {
+		for name, valueOrValueFrom := range values {
+			var value string
+			if valueOrValueFrom.Value != "" {
+				rendered, err := RenderClusterTemplate(c, valueOrValueFrom.Value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to render value for %s", name)
+				}
+				value = rendered
+			} else {
+				resolved, err := valueOrValueFrom.String()
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to read value for %s", name)
+				}
+				value = resolved
+			}
+
+			configValue := rendered.Values[name]
+			configValue.Value = value
+			rendered.Values[name] = configValue
+		}
+	}
+
+	return rendered, nil
+}
+
+// RenderClusterTemplate will render value as a go template with the cluster config as the data.
+// referencing a field that the cluster config does not have is an error
+func RenderClusterTemplate(c *types.ClusterConfig, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New("cluster").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, c); err != nil {
+		return "", errors.Wrap(err, "failed to execute template")
+	}
+
+	return buf.String(), nil
+}
+
+// MarshalKOTSConfigValues will return the config values as a kots ConfigValues yaml document
+func MarshalKOTSConfigValues(configValuesSpec *kotsv1beta1.ConfigValuesSpec) ([]byte, error) {
+	configValues := kotsv1beta1.ConfigValues{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kots.io/v1beta1",
+			Kind:       "ConfigValues",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "automated-config-values",
+		},
+		Spec: *configValuesSpec,
+	}
+	kotsKinds := kotsutil.KotsKinds{
+		ConfigValues: &configValues,
+	}
+	b, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal config values")
+	}
+
+	return []byte(b), nil
+}
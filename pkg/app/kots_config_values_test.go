@@ -0,0 +1,149 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RenderKOTSConfigValues(t *testing.T) {
+	os.Setenv("TEST_GRID_DB_PASSWORD", "secret")
+	defer os.Unsetenv("TEST_GRID_DB_PASSWORD")
+	os.Setenv("TEST_GRID_API_TOKEN", "{{ not a template")
+	defer os.Unsetenv("TEST_GRID_API_TOKEN")
+
+	clusterConfig := &types.ClusterConfig{
+		Name:     "cluster-a",
+		Provider: "aws",
+		Region:   "us-west-1",
+		Version:  "1.18",
+	}
+
+	tests := []struct {
+		name        string
+		kotsAppSpec *types.KOTSApplicationSpec
+		expected    *kotsv1beta1.ConfigValuesSpec
+		expectError bool
+	}{
+		{
+			name:        "no config values",
+			kotsAppSpec: &types.KOTSApplicationSpec{},
+			expected:    nil,
+		},
+		{
+			name: "templated config values",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				ConfigValues: &kotsv1beta1.ConfigValuesSpec{
+					Values: map[string]kotsv1beta1.ConfigValue{
+						"hostname": {
+							Value: "{{ .Name }}.example.com",
+						},
+						"storage_class": {
+							Default: "gp2-{{ .Region }}",
+						},
+					},
+				},
+			},
+			expected: &kotsv1beta1.ConfigValuesSpec{
+				Values: map[string]kotsv1beta1.ConfigValue{
+					"hostname": {
+						Value: "cluster-a.example.com",
+					},
+					"storage_class": {
+						Default: "gp2-us-west-1",
+					},
+				},
+			},
+		},
+		{
+			name: "cluster overrides",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				ConfigValues: &kotsv1beta1.ConfigValuesSpec{
+					Values: map[string]kotsv1beta1.ConfigValue{
+						"hostname": {
+							Value: "default.example.com",
+						},
+					},
+				},
+				ConfigValuesFrom: map[string]types.ValueOrValueFrom{
+					"db_password": {
+						ValueFrom: &types.ValueFrom{
+							OSEnv: "TEST_GRID_DB_PASSWORD",
+						},
+					},
+				},
+				ClusterConfigValues: map[string]map[string]types.ValueOrValueFrom{
+					"cluster-a": {
+						"hostname": {
+							Value: "{{ .Provider }}-{{ .Version }}.example.com",
+						},
+					},
+					"cluster-b": {
+						"hostname": {
+							Value: "b.example.com",
+						},
+					},
+				},
+			},
+			expected: &kotsv1beta1.ConfigValuesSpec{
+				Values: map[string]kotsv1beta1.ConfigValue{
+					"hostname": {
+						Value: "aws-1.18.example.com",
+					},
+					"db_password": {
+						Value: "secret",
+					},
+				},
+			},
+		},
+		{
+			name: "value from is not rendered",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				ConfigValuesFrom: map[string]types.ValueOrValueFrom{
+					"api_token": {
+						ValueFrom: &types.ValueFrom{
+							OSEnv: "TEST_GRID_API_TOKEN",
+						},
+					},
+				},
+			},
+			expected: &kotsv1beta1.ConfigValuesSpec{
+				Values: map[string]kotsv1beta1.ConfigValue{
+					"api_token": {
+						Value: "{{ not a template",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid template",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				ConfigValuesFrom: map[string]types.ValueOrValueFrom{
+					"hostname": {
+						Value: "{{ .NotAField }}",
+					},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := RenderKOTSConfigValues(clusterConfig, test.kotsAppSpec)
+			if test.expectError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+const (
+	DefaultKOTSLicenseEndpoint = "https://replicated.app"
+)
+
+// loadKOTSLicense will write the license from the spec, or download it using the license id,
+// into a temp file and parse it. the caller is responsible for deleting the file
+func loadKOTSLicense(kotsAppSpec *types.KOTSApplicationSpec) (string, *kotsv1beta1.License, error) {
+	var licenseFilePath string
+	if kotsAppSpec.License != nil {
+		licenseData, err := kotsAppSpec.License.String()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to read license")
+		}
+
+		licenseFile, err := ioutil.TempFile("", "kots")
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to create temp license file")
+		}
+		defer licenseFile.Close()
+
+		if _, err := licenseFile.WriteString(licenseData); err != nil {
+			os.RemoveAll(licenseFile.Name())
+			return "", nil, errors.Wrap(err, "failed to write license file")
+		}
+
+		licenseFilePath = licenseFile.Name()
+	} else if kotsAppSpec.LicenseID != "" {
+		endpoint := kotsAppSpec.LicenseEndpoint
+		if endpoint == "" {
+			endpoint = DefaultKOTSLicenseEndpoint
+		}
+
+		downloadedFilePath, err := downloadKOTSLicense(endpoint, kotsAppSpec.App, kotsAppSpec.LicenseID)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to download license")
+		}
+
+		licenseFilePath = downloadedFilePath
+	} else {
+		return "", nil, errors.New("license or licenseID is required")
+	}
+
+	license, err := kotsutil.LoadLicenseFromPath(licenseFilePath)
+	if err != nil {
+		os.RemoveAll(licenseFilePath)
+		return "", nil, errors.Wrap(err, "failed to load license")
+	}
+
+	return licenseFilePath, license, nil
+}
+
+// the caller is responsible for deleting the file
+func downloadKOTSLicense(endpoint string, appSlug string, licenseID string) (string, error) {
+	url := fmt.Sprintf("%s/license/%s", strings.TrimSuffix(endpoint, "/"), appSlug)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create new request")
+	}
+
+	req.SetBasicAuth(licenseID, licenseID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status code downloading license: %d", resp.StatusCode)
+	}
+
+	licenseFile, err := ioutil.TempFile("", "kots")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp license file")
+	}
+	defer licenseFile.Close()
+
+	_, err = io.Copy(licenseFile, resp.Body)
+	if err != nil {
+		os.RemoveAll(licenseFile.Name())
+		return "", errors.Wrap(err, "failed to copy file")
+	}
+
+	return licenseFile.Name(), nil
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLicense = `apiVersion: kots.io/v1beta1
+kind: License
+spec:
+  appSlug: my-app
+  channelName: Stable
+`
+
+func Test_loadKOTSLicense(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		licenseID, _, ok := r.BasicAuth()
+		if !ok || licenseID != "license-id" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/license/my-app":
+			fmt.Fprint(w, testLicense)
+		case "/license/not-a-license":
+			fmt.Fprint(w, "not a license")
+		case "/license/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "license")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	licensePath := filepath.Join(dir, "license.yaml")
+	require.NoError(t, ioutil.WriteFile(licensePath, []byte(testLicense), 0644))
+
+	tests := []struct {
+		name        string
+		kotsAppSpec *types.KOTSApplicationSpec
+		expectError bool
+	}{
+		{
+			name: "license id",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "my-app",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL,
+			},
+		},
+		{
+			name: "license endpoint with trailing slash",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "my-app",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL + "/",
+			},
+		},
+		{
+			name: "license file",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App: "my-app",
+				License: &types.ValueOrValueFrom{
+					ValueFrom: &types.ValueFrom{
+						File: licensePath,
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized license id",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "my-app",
+				LicenseID:       "other-license-id",
+				LicenseEndpoint: server.URL,
+			},
+			expectError: true,
+		},
+		{
+			name: "unknown app",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "other-app",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL,
+			},
+			expectError: true,
+		},
+		{
+			name: "server error",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "error",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL,
+			},
+			expectError: true,
+		},
+		{
+			name: "downloaded file is not a license",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:             "not-a-license",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL,
+			},
+			expectError: true,
+		},
+		{
+			name: "missing license file",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App: "my-app",
+				License: &types.ValueOrValueFrom{
+					ValueFrom: &types.ValueFrom{
+						File: filepath.Join(dir, "missing.yaml"),
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "no license",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App: "my-app",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			licenseFilePath, license, err := loadKOTSLicense(test.kotsAppSpec)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer os.RemoveAll(licenseFilePath)
+
+			assert.Equal(t, "my-app", license.Spec.AppSlug)
+			assert.Equal(t, "Stable", license.Spec.ChannelName)
+
+			data, err := ioutil.ReadFile(licenseFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, testLicense, string(data))
+		})
+	}
+}
+
+func Test_DeployLoadsLicenseOnce(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprint(w, testLicense)
+	}))
+	defer server.Close()
+
+	g := &types.GridConfig{
+		Name: "grid",
+		ClusterConfigs: []*types.ClusterConfig{
+			{Name: "a", Status: &types.ClusterStatus{Phase: types.ClusterPhasePending}},
+			{Name: "b", Status: &types.ClusterStatus{Phase: types.ClusterPhasePending}},
+			{Name: "c", Status: &types.ClusterStatus{Phase: types.ClusterPhasePending}},
+		},
+	}
+	a := &types.Application{
+		Spec: types.ApplicationSpec{
+			KOTSApplicationSpec: &types.KOTSApplicationSpec{
+				App:             "my-app",
+				LicenseID:       "license-id",
+				LicenseEndpoint: server.URL,
+			},
+		},
+	}
+
+	_, err := Deploy(context.Background(), g, a, DeployOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
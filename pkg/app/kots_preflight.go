@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/cluster"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/logger"
+)
+
+var errPreflightResultsTimeout = errors.New("timed out waiting for preflight results")
+
+type kotsPreflightResultResponse struct {
+	PreflightResult struct {
+		Result string `json:"result"`
+	} `json:"preflightResult"`
+}
+
+type kotsPreflightResults struct {
+	Results []kotsPreflightResult `json:"results"`
+}
+
+type kotsPreflightResult struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	IsPass  bool   `json:"isPass"`
+	IsWarn  bool   `json:"isWarn"`
+	IsFail  bool   `json:"isFail"`
+}
+
+// collectKOTSPreflights will wait for the preflight results, record them on the cluster
+// and check them against the policy in the app spec. when the app spec has no policy,
+// not getting any results is not an error
+func collectKOTSPreflights(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec, appSlug string, log logger.Logger) error {
+	preflightResults, err := waitForKOTSPreflightResults(ctx, c, kotsNamespace(kotsAppSpec), appSlug, 5*time.Minute)
+	if errors.Cause(err) == errPreflightResultsTimeout && !hasPreflightPolicy(kotsAppSpec) {
+		log.Info("No preflight results")
+		c.PreflightResults = nil
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get preflight results")
+	}
+	c.PreflightResults = preflightResults
+
+	if err := checkPreflightPolicy(kotsAppSpec, preflightResults); err != nil {
+		return errors.Wrap(err, "preflight policy")
+	}
+
+	return nil
+}
+
+// waitForKOTSPreflightResults will poll the admin console until the preflight checks
+// for the app have completed, and return the results
+func waitForKOTSPreflightResults(ctx context.Context, c *types.ClusterConfig, namespace string, appSlug string, timeout time.Duration) ([]types.PreflightResult, error) {
+	// the kots cli authenticates to the admin console api with this secret
+	authString, err := cluster.GetSecretValue(ctx, c, namespace, "kotsadm-authstring", "kotsadm-authstring")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kotsadm authstring")
+	}
+
+	headers := map[string]string{
+		"Authorization": authString,
+	}
+	path := fmt.Sprintf("api/v1/app/%s/preflight/result", appSlug)
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		b, err := cluster.ProxyServiceGet(ctx, c, namespace, "kotsadm:3000", path, headers)
+		if err == nil {
+			results, err := parseKOTSPreflightResults(b)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse preflight results")
+			}
+
+			if results != nil {
+				return results, nil
+			}
+		}
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, errPreflightResultsTimeout
+}
+
+// parseKOTSPreflightResults returns nil if the preflights have not completed
+func parseKOTSPreflightResults(b []byte) ([]types.PreflightResult, error) {
+	response := kotsPreflightResultResponse{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	if response.PreflightResult.Result == "" {
+		return nil, nil
+	}
+
+	kotsResults := kotsPreflightResults{}
+	if err := json.Unmarshal([]byte(response.PreflightResult.Result), &kotsResults); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal results")
+	}
+
+	results := []types.PreflightResult{}
+	for _, r := range kotsResults.Results {
+		state := types.PreflightStatePass
+		if r.IsFail {
+			state = types.PreflightStateFail
+		} else if r.IsWarn {
+			state = types.PreflightStateWarn
+		}
+
+		results = append(results, types.PreflightResult{
+			Check:   r.Title,
+			State:   state,
+			Message: r.Message,
+		})
+	}
+
+	return results, nil
+}
+
+func hasPreflightPolicy(kotsAppSpec *types.KOTSApplicationSpec) bool {
+	return kotsAppSpec.FailOnPreflightWarn || kotsAppSpec.FailOnPreflightFail
+}
+
+// checkPreflightPolicy will return an error if the results violate the fail on warn
+// or fail on fail policy in the app spec
+func checkPreflightPolicy(kotsAppSpec *types.KOTSApplicationSpec, results []types.PreflightResult) error {
+	for _, r := range results {
+		if r.State == types.PreflightStateFail && (kotsAppSpec.FailOnPreflightFail || kotsAppSpec.FailOnPreflightWarn) {
+			return errors.Errorf("preflight check %q failed: %s", r.Check, r.Message)
+		}
+		if r.State == types.PreflightStateWarn && kotsAppSpec.FailOnPreflightWarn {
+			return errors.Errorf("preflight check %q warned: %s", r.Check, r.Message)
+		}
+	}
+
+	return nil
+}
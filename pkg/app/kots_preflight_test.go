@@ -0,0 +1,82 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseKOTSPreflightResults(t *testing.T) {
+	req := require.New(t)
+
+	pending, err := parseKOTSPreflightResults([]byte(`{"preflightResult":{"result":""}}`))
+	req.NoError(err)
+	assert.Nil(t, pending)
+
+	completed, err := parseKOTSPreflightResults([]byte(`{"preflightResult":{"result":"{\"results\":[{\"title\":\"Kubernetes Version\",\"message\":\"ok\",\"isPass\":true},{\"title\":\"Memory\",\"message\":\"low\",\"isWarn\":true},{\"title\":\"Storage\",\"message\":\"none\",\"isFail\":true}]}"}}`))
+	req.NoError(err)
+	assert.Equal(t, []types.PreflightResult{
+		{Check: "Kubernetes Version", State: types.PreflightStatePass, Message: "ok"},
+		{Check: "Memory", State: types.PreflightStateWarn, Message: "low"},
+		{Check: "Storage", State: types.PreflightStateFail, Message: "none"},
+	}, completed)
+}
+
+func Test_checkPreflightPolicy(t *testing.T) {
+	warn := []types.PreflightResult{
+		{Check: "Kubernetes Version", State: types.PreflightStatePass},
+		{Check: "Memory", State: types.PreflightStateWarn},
+	}
+	fail := []types.PreflightResult{
+		{Check: "Storage", State: types.PreflightStateFail},
+	}
+
+	tests := []struct {
+		name        string
+		kotsAppSpec *types.KOTSApplicationSpec
+		results     []types.PreflightResult
+		expectError bool
+	}{
+		{
+			name:        "no policy",
+			kotsAppSpec: &types.KOTSApplicationSpec{},
+			results:     fail,
+		},
+		{
+			name:        "fail on fail with a warning",
+			kotsAppSpec: &types.KOTSApplicationSpec{FailOnPreflightFail: true},
+			results:     warn,
+		},
+		{
+			name:        "fail on fail with a failure",
+			kotsAppSpec: &types.KOTSApplicationSpec{FailOnPreflightFail: true},
+			results:     fail,
+			expectError: true,
+		},
+		{
+			name:        "fail on warn with a warning",
+			kotsAppSpec: &types.KOTSApplicationSpec{FailOnPreflightWarn: true},
+			results:     warn,
+			expectError: true,
+		},
+		{
+			name:        "fail on warn with a failure",
+			kotsAppSpec: &types.KOTSApplicationSpec{FailOnPreflightWarn: true},
+			results:     fail,
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkPreflightPolicy(test.kotsAppSpec, test.results)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// WaitForApplicationReady will poll the status of the application on the cluster
+// until it is ready, or return an error when the timeout is reached
+func WaitForApplicationReady(ctx context.Context, c *types.ClusterConfig, a *types.Application, timeout time.Duration) error {
+	if a.Spec.KOTSApplicationSpec == nil {
+		return errors.New("only kots applications report status")
+	}
+
+	pathToLicense, license, err := loadKOTSLicense(a.Spec.KOTSApplicationSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to load license")
+	}
+	os.RemoveAll(pathToLicense)
+
+	return waitForKOTSApplicationReady(ctx, c, a.Spec.KOTSApplicationSpec, license.Spec.AppSlug, timeout)
+}
+
+func waitForKOTSApplicationReady(ctx context.Context, c *types.ClusterConfig, kotsAppSpec *types.KOTSApplicationSpec, appSlug string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		isReady, err := isApplicationReady(ctx, c, kotsAppSpec, appSlug)
+		if err == nil && isReady {
+			return nil
+		}
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return errors.New("timed out waiting for application to be ready")
+}
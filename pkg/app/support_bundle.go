@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/cluster"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+)
+
+const defaultSupportBundleSpec = `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: kubectl-grid
+spec:
+  collectors:
+    - clusterInfo: {}
+    - clusterResources: {}
+`
+
+type SupportBundleOptions struct {
+	// ClusterName limits collection to a single cluster when set
+	ClusterName string
+	// SpecPath is the path to a support bundle spec. When empty, the spec from
+	// the application is used, or a default spec if there is no application
+	SpecPath    string
+	Application *types.Application
+	OutputDir   string
+}
+
+// CollectSupportBundles will collect a support bundle from each cluster in the grid in parallel,
+// and return the paths to the bundles that were collected
+func CollectSupportBundles(ctx context.Context, g *types.GridConfig, opts SupportBundleOptions) ([]string, error) {
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	bundlePaths := []string{}
+	errs := []string{}
+
+	appSlug, err := supportBundleAppSlug(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app slug")
+	}
+
+	for _, c := range g.ReadyClusters() {
+		if opts.ClusterName != "" && c.Name != opts.ClusterName {
+			continue
+		}
+
+		wg.Add(1)
+		go func(c *types.ClusterConfig) {
+			defer wg.Done()
+
+			bundlePath, err := collectSupportBundle(ctx, g.Name, c, appSlug, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("cluster %s: %s", c.Name, err.Error()))
+				return
+			}
+			bundlePaths = append(bundlePaths, bundlePath)
+		}(c)
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return bundlePaths, errors.Errorf("failed to collect support bundles: %v", errs)
+	}
+
+	return bundlePaths, nil
+}
+
+// supportBundleAppSlug will return the slug of the kots application to get the support bundle
+// spec for, or an empty string when the default spec or the spec path in opts is used
+func supportBundleAppSlug(opts SupportBundleOptions) (string, error) {
+	if opts.SpecPath != "" || opts.Application == nil || opts.Application.Spec.KOTSApplicationSpec == nil {
+		return "", nil
+	}
+
+	// the license is the same for every cluster, so only load it once
+	licenseFilePath, license, err := loadKOTSLicense(opts.Application.Spec.KOTSApplicationSpec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load license")
+	}
+	defer os.RemoveAll(licenseFilePath)
+
+	return license.Spec.AppSlug, nil
+}
+
+func collectSupportBundle(ctx context.Context, gridName string, c *types.ClusterConfig, appSlug string, opts SupportBundleOptions) (string, error) {
+	specPath := opts.SpecPath
+	if specPath == "" {
+		spec, err := getSupportBundleSpec(ctx, c, opts.Application, appSlug)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get support bundle spec")
+		}
+
+		specFile, err := ioutil.TempFile("", "support-bundle")
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create temp file")
+		}
+		defer os.RemoveAll(specFile.Name())
+		if err := ioutil.WriteFile(specFile.Name(), spec, 0644); err != nil {
+			return "", errors.Wrap(err, "failed to write support bundle spec")
+		}
+		specPath = specFile.Name()
+	}
+
+	bundleDir := filepath.Join(opts.OutputDir, gridName)
+	if err := os.MkdirAll(bundleDir, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create output dir")
+	}
+	bundlePath := filepath.Join(bundleDir, fmt.Sprintf("%s.tar.gz", c.Name))
+
+	if err := kubectl.SupportBundle(ctx, c, specPath, bundlePath); err != nil {
+		return "", errors.Wrap(err, "failed to collect support bundle")
+	}
+
+	return bundlePath, nil
+}
+
+// getSupportBundleSpec will return the support bundle spec from the admin console when the
+// application is a kots application, or the default spec otherwise
+func getSupportBundleSpec(ctx context.Context, c *types.ClusterConfig, a *types.Application, appSlug string) ([]byte, error) {
+	if a == nil || a.Spec.KOTSApplicationSpec == nil {
+		return []byte(defaultSupportBundleSpec), nil
+	}
+
+	path := fmt.Sprintf("api/v1/troubleshoot/app/%s", appSlug)
+	spec, err := cluster.ProxyServiceGet(ctx, c, kotsNamespace(a.Spec.KOTSApplicationSpec), "kotsadm:3000", path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get support bundle spec from admin console")
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// protectedNamespaces are never deleted by undeploy, because the cluster depends on them
+var protectedNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// Undeploy will remove the application from each cluster in the grid, leaving the
+// clusters running so they can be reused. confirm is called once per cluster, and
+// the cluster is skipped if it returns false. The clusters the app was removed from
+// are updated in g, even when it fails to be removed from a later cluster
+func Undeploy(ctx context.Context, g *types.GridConfig, a *types.Application, confirm func(c *types.ClusterConfig) bool) error {
+	if a.Spec.KOTSApplicationSpec == nil {
+		return errors.New("only kots applications can be undeployed")
+	}
+
+	// undeploy deletes the whole namespace, so refuse to delete one that isn't the app's own
+	namespace := kotsNamespace(a.Spec.KOTSApplicationSpec)
+	if namespace == "" {
+		return errors.New("application has no namespace to remove")
+	}
+	if protectedNamespaces[namespace] {
+		return errors.Errorf("refusing to undeploy from namespace %s", namespace)
+	}
+
+	for _, c := range g.ReadyClusters() {
+		if confirm != nil && !confirm(c) {
+			continue
+		}
+
+		if err := undeployKOTSApplication(ctx, c, a.Spec.KOTSApplicationSpec); err != nil {
+			return errors.Wrapf(err, "failed to undeploy from cluster %s", c.Name)
+		}
+		clearDeployedApplication(c, a.Name, namespace)
+	}
+
+	return nil
+}
+
+// clearDeployedApplication will remove what deploy recorded on the cluster about the
+// application in the namespace
+func clearDeployedApplication(c *types.ClusterConfig, name string, namespace string) {
+	c.RemoveDeployedApplication(name)
+	c.RemoveCondition(types.ClusterConditionAppDeployed)
+
+	// the admin console was removed with the namespace
+	if c.AdminConsole != nil && c.AdminConsole.Namespace == namespace {
+		c.AdminConsole = nil
+	}
+}
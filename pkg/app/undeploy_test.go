@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UndeployProtectedNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		kotsAppSpec *types.KOTSApplicationSpec
+	}{
+		{
+			name: "default namespace",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App:       "my-app",
+				Namespace: "default",
+			},
+		},
+		{
+			name: "admin console in kube-system",
+			kotsAppSpec: &types.KOTSApplicationSpec{
+				App: "my-app",
+				AdminConsole: &types.KOTSAdminConsoleSpec{
+					Namespace: "kube-system",
+				},
+			},
+		},
+		{
+			name:        "no namespace",
+			kotsAppSpec: &types.KOTSApplicationSpec{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := &types.GridConfig{
+				ClusterConfigs: []*types.ClusterConfig{
+					{Name: "a"},
+				},
+			}
+			a := &types.Application{
+				Spec: types.ApplicationSpec{
+					KOTSApplicationSpec: test.kotsAppSpec,
+				},
+			}
+
+			err := Undeploy(context.Background(), g, a, func(c *types.ClusterConfig) bool {
+				t.Errorf("cluster %s should not be undeployed", c.Name)
+				return false
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_clearDeployedApplication(t *testing.T) {
+	c := &types.ClusterConfig{
+		Name: "a",
+		AdminConsole: &types.AdminConsoleConfig{
+			Namespace: "my-app",
+		},
+	}
+	c.SetDeployedApplication("other-app")
+	c.SetDeployedApplication("my-app")
+	c.SetCondition(types.ClusterConditionAppDeployed, types.ConditionStatusTrue, "")
+
+	clearDeployedApplication(c, "my-app", "my-app")
+
+	assert.Nil(t, c.AdminConsole)
+	assert.Nil(t, c.GetCondition(types.ClusterConditionAppDeployed))
+	if assert.Len(t, c.Applications, 1) {
+		assert.Equal(t, "other-app", c.Applications[0].Name)
+	}
+
+	// an admin console in another namespace was not removed
+	c.AdminConsole = &types.AdminConsoleConfig{
+		Namespace: "other-app",
+	}
+	clearDeployedApplication(c, "my-app", "my-app")
+	assert.NotNil(t, c.AdminConsole)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "admin-console",
+		Short:         "Open a port-forward to the KOTS admin console on a cluster in the grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return errors.Wrap(err, "failed to list grids")
+			}
+
+			for _, g := range grids {
+				if g.Name != v.GetString("grid") {
+					continue
+				}
+
+				for _, c := range g.ReadyClusters() {
+					if c.Name != v.GetString("cluster") {
+						continue
+					}
+
+					if c.AdminConsole == nil {
+						return errors.Errorf("no admin console has been deployed to cluster %s", c.Name)
+					}
+
+					password := c.AdminConsole.SharedPassword
+					if password == "" {
+						password = "(set in the application spec)"
+					}
+
+					localPort := v.GetInt("port")
+					fmt.Printf("Admin console: http://localhost:%d\n", localPort)
+					fmt.Printf("Password: %s\n", password)
+					fmt.Printf("Press Ctrl-C to stop the port-forward\n")
+
+					return kubectl.PortForward(cmd.Context(), c, c.AdminConsole.Namespace, "svc/kotsadm", localPort, 3000)
+				}
+
+				return errors.New("ready cluster not found")
+			}
+
+			return errors.New("grid not found")
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
+	cmd.Flags().StringP("cluster", "c", "", "Name of the cluster")
+	cmd.Flags().Int("port", 8800, "Local port to forward to the admin console")
+
+	cmd.MarkFlagRequired("grid")
+	cmd.MarkFlagRequired("cluster")
+
+	return cmd
+}
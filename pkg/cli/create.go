@@ -2,10 +2,13 @@ package cli
 
 import (
 	"io/ioutil"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"sigs.k8s.io/yaml"
@@ -40,15 +43,43 @@ func CreateCmd() *cobra.Command {
 				gridSpec.Name = v.GetString("name")
 			}
 
-			if err := grid.Create(v.GetString("config-file"), &gridSpec); err != nil {
+			if err := applyLogFormat(v, &gridSpec.Spec.Logger); err != nil {
+				return err
+			}
+
+			stopTracing, err := startTracing(cmd.Context(), v)
+			if err != nil {
+				return err
+			}
+			defer stopTracing()
+
+			createStep := &types.StepResult{
+				Name:      types.StepCreate,
+				StartedAt: time.Now(),
+			}
+			createOpts := grid.CreateOptions{
+				Parallelism:         v.GetInt("parallelism"),
+				RollbackOnInterrupt: v.GetBool("rollback-on-interrupt"),
+				Resume:              v.GetBool("resume"),
+			}
+			out := messageOutput(gridSpec.Spec.Logger)
+			ctx, stopWatch := startWatch(watch.WithOutput(cmd.Context(), out), isWatching(v))
+			err = grid.Create(ctx, v.GetString("config-file"), &gridSpec, createOpts)
+			stopWatch()
+			if err != nil {
 				return errors.Wrap(err, "failed to create cluster")
 			}
+			createStep.FinishedAt = time.Now()
 
 			if v.GetString("app") == "" {
 				return nil
 			}
 
-			if err := deployApp(v.GetString("config-file"), gridSpec.Name, v.GetString("app")); err != nil {
+			deployOpts := app.DeployOptions{
+				Parallelism: v.GetInt("parallelism"),
+				LoggerSpec:  gridSpec.Spec.Logger,
+			}
+			if err := deployApp(watch.WithOutput(cmd.Context(), out), v.GetString("config-file"), gridSpec.Name, v.GetString("app"), deployOpts, isWatching(v), createStep, out); err != nil {
 				return errors.Wrap(err, "failed to deploy app")
 			}
 
@@ -60,6 +91,10 @@ func CreateCmd() *cobra.Command {
 	cmd.Flags().String("from-yaml", "", "Path to YAML manifest describing the grid to create")
 	cmd.Flags().String("like", "", "Name of an existing grid to clone, into a new grid")
 	cmd.Flags().String("app", "", "Path to YAML manifest describing the application to deploy after grid is created")
+	cmd.Flags().Int("parallelism", 0, "Maximum number of clusters to create or deploy to at once, 0 for no limit")
+	cmd.Flags().Bool("rollback-on-interrupt", false, "Delete the clusters that were created when the create is interrupted")
+	cmd.Flags().Bool("resume", false, "Create the remaining clusters in a grid that was interrupted or failed")
+	addWatchFlags(cmd)
 
 	return cmd
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/replicatedhq/kubectl-grid/pkg/grid"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"sigs.k8s.io/yaml"
@@ -31,7 +32,21 @@ func DeleteCmd() *cobra.Command {
 				return err
 			}
 
-			if err := grid.Delete(v.GetString("config-file"), gridSpec); err != nil {
+			if err := applyLogFormat(v, &gridSpec.Spec.Logger); err != nil {
+				return err
+			}
+
+			stopTracing, err := startTracing(cmd.Context(), v)
+			if err != nil {
+				return err
+			}
+			defer stopTracing()
+
+			deleteOpts := grid.DeleteOptions{
+				Parallelism: v.GetInt("parallelism"),
+			}
+			ctx := watch.WithOutput(cmd.Context(), messageOutput(gridSpec.Spec.Logger))
+			if err := grid.Delete(ctx, v.GetString("config-file"), gridSpec, deleteOpts); err != nil {
 				return err
 			}
 
@@ -42,6 +57,7 @@ func DeleteCmd() *cobra.Command {
 	cmd.Flags().StringP("name", "n", "", "Name of the grid, overriding the name in the yaml metadata.name field")
 	cmd.Flags().String("from-yaml", "", "Path to YAML manifest describing the grid to delete")
 	cmd.Flags().String("like", "", "Name of an existing grid to clone, into a new grid")
+	cmd.Flags().Int("parallelism", 0, "Maximum number of clusters to delete at once, 0 for no limit")
 
 	return cmd
 }
@@ -1,12 +1,18 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/app"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"sigs.k8s.io/yaml"
@@ -23,17 +29,43 @@ func DeployCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
 
-			return deployApp(v.GetString("config-file"), v.GetString("grid"), v.GetString("app"))
+			if v.GetBool("render") {
+				return renderAppConfigValues(v.GetString("config-file"), v.GetString("grid"), v.GetString("app"))
+			}
+
+			opts := app.DeployOptions{
+				SupportBundleDir: v.GetString("support-bundle-on-failure"),
+				Parallelism:      v.GetInt("parallelism"),
+			}
+			if err := applyLogFormat(v, &opts.LoggerSpec); err != nil {
+				return err
+			}
+
+			stopTracing, err := startTracing(cmd.Context(), v)
+			if err != nil {
+				return err
+			}
+			defer stopTracing()
+
+			out := messageOutput(opts.LoggerSpec)
+			ctx := watch.WithOutput(cmd.Context(), out)
+			return deployApp(ctx, v.GetString("config-file"), v.GetString("grid"), v.GetString("app"), opts, isWatching(v), nil, out)
 		},
 	}
 
 	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
 	cmd.Flags().String("app", "", "Path to YAML manifest describing the application to deploy")
+	cmd.Flags().String("support-bundle-on-failure", "", "Collect a support bundle into this directory from each cluster the app fails to deploy to")
+	cmd.Flags().Bool("render", false, "Print the resolved config values for each cluster instead of deploying")
+	cmd.Flags().Int("parallelism", 0, "Maximum number of clusters to deploy to at once, 0 for no limit")
+	addWatchFlags(cmd)
 
 	return cmd
 }
 
-func deployApp(configFile string, gridName string, appSpecFilename string) error {
+// deployApp will deploy the app and record the run. createStep is recorded as the first step
+// on each cluster when the grid was created for this deploy. The results are printed to out
+func deployApp(ctx context.Context, configFile string, gridName string, appSpecFilename string, opts app.DeployOptions, watching bool, createStep *types.StepResult, out io.Writer) error {
 	data, err := ioutil.ReadFile(appSpecFilename)
 	if err != nil {
 		return errors.Wrap(err, "failed to read app spec file")
@@ -51,8 +83,36 @@ func deployApp(configFile string, gridName string, appSpecFilename string) error
 
 	for _, g := range grids {
 		if g.Name == gridName {
-			if err := app.Deploy(g, &application); err != nil {
-				return errors.Wrap(err, "failed to deploy app")
+			r := runs.NewRun(runs.KindDeploy, g.Name, application.Name, data)
+			deployCtx, stopWatch := startWatch(ctx, watching)
+			results, deployErr := app.Deploy(deployCtx, g, &application, opts)
+			stopWatch()
+			r.FinishedAt = time.Now()
+
+			// deploy records admin console details and preflight results on each cluster, even if some clusters failed
+			if err := grid.Update(configFile, g); err != nil {
+				return errors.Wrap(err, "failed to update grid config")
+			}
+
+			printPreflightMatrix(out, g)
+			if len(application.Spec.Checks) > 0 {
+				fmt.Fprintln(out)
+				printCheckMatrix(out, g)
+			}
+
+			// the run is recorded when any cluster was deployed to, so that partial failures are kept
+			if results != nil {
+				if createStep != nil {
+					r.StartedAt = createStep.StartedAt
+				}
+				if err := recordDeployRun(runs.Dir(configFile), r, g, results, createStep); err != nil {
+					return errors.Wrap(err, "failed to record run")
+				}
+				fmt.Fprintf(out, "\nRecorded run %s\n", r.ID)
+			}
+
+			if deployErr != nil {
+				return errors.Wrap(deployErr, "failed to deploy app")
 			}
 
 			return nil
@@ -61,3 +121,95 @@ func deployApp(configFile string, gridName string, appSpecFilename string) error
 
 	return errors.New("unable to find grid")
 }
+
+func recordDeployRun(runsDir string, r *runs.Run, g *types.GridConfig, results []*app.ClusterDeployResult, createStep *types.StepResult) error {
+	for _, result := range results {
+		for _, c := range g.ClusterConfigs {
+			if c.Name != result.ClusterName {
+				continue
+			}
+
+			clusterRun := runs.NewClusterRun(c)
+			clusterRun.SetError(result.Error)
+			clusterRun.StartedAt = result.StartedAt
+			clusterRun.FinishedAt = result.FinishedAt
+			clusterRun.PreflightResults = c.PreflightResults
+			clusterRun.CheckResults = c.CheckResults
+			if createStep != nil {
+				clusterRun.Steps = append(clusterRun.Steps, *createStep)
+			}
+			clusterRun.Steps = append(clusterRun.Steps, result.Steps...)
+			if result.SupportBundlePath != "" {
+				if err := runs.AddArtifact(runsDir, r, clusterRun, result.SupportBundlePath); err != nil {
+					return errors.Wrapf(err, "failed to add support bundle for cluster %s", c.Name)
+				}
+			}
+
+			r.Clusters = append(r.Clusters, clusterRun)
+		}
+	}
+
+	return runs.Save(runsDir, r)
+}
+
+// printCheckMatrix prints a table with a row for each application check
+// and a column for each cluster
+func printCheckMatrix(out io.Writer, g *types.GridConfig) {
+	printClusterMatrix(out, g, "CHECK", func(c *types.ClusterConfig) []matrixResult {
+		results := []matrixResult{}
+		for _, r := range c.CheckResults {
+			results = append(results, matrixResult{Row: r.Check, State: r.State})
+		}
+		return results
+	})
+}
+
+func renderAppConfigValues(configFile string, gridName string, appSpecFilename string) error {
+	data, err := ioutil.ReadFile(appSpecFilename)
+	if err != nil {
+		return errors.Wrap(err, "failed to read app spec file")
+	}
+
+	application := types.Application{}
+	if err := yaml.Unmarshal(data, &application); err != nil {
+		return errors.Wrap(err, "failed to unmarshal app spec")
+	}
+
+	if application.Spec.KOTSApplicationSpec == nil {
+		return errors.New("only kots applications have config values")
+	}
+
+	grids, err := grid.List(configFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to list grids")
+	}
+
+	for _, g := range grids {
+		if g.Name != gridName {
+			continue
+		}
+
+		for _, c := range g.ReadyClusters() {
+			configValues, err := app.RenderKOTSConfigValues(c, application.Spec.KOTSApplicationSpec)
+			if err != nil {
+				return errors.Wrapf(err, "failed to render config values for cluster %s", c.Name)
+			}
+
+			fmt.Printf("# cluster: %s\n", c.Name)
+			if configValues == nil {
+				fmt.Printf("# no config values\n---\n")
+				continue
+			}
+
+			b, err := app.MarshalKOTSConfigValues(configValues)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal config values")
+			}
+			fmt.Printf("%s---\n", b)
+		}
+
+		return nil
+	}
+
+	return errors.New("unable to find grid")
+}
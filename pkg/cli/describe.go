@@ -24,6 +24,7 @@ func DescribeCmd() *cobra.Command {
 	cmd.PersistentFlags().StringP("output", "o", "", "Output format (empty or json)")
 
 	cmd.AddCommand(DescribeGridCmd())
+	cmd.AddCommand(DescribeClusterCmd())
 
 	return cmd
 }
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/cluster"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	redacted          = "REDACTED"
+	recentEventsLimit = 10
+)
+
+func DescribeClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "cluster",
+		Short:         "Describe a cluster in a grid",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return err
+			}
+
+			for _, g := range grids {
+				if g.Name != v.GetString("grid") {
+					continue
+				}
+
+				for _, c := range g.ClusterConfigs {
+					if c.Name != args[0] {
+						continue
+					}
+
+					// events are only available from a cluster that was created
+					var events []corev1.Event
+					var eventsErr error
+					if c.Kubeconfig != "" {
+						events, eventsErr = cluster.ListRecentEvents(cmd.Context(), c, recentEventsLimit)
+					}
+
+					if v.GetString("output") == "json" {
+						printJSONClusterDescription(c, events)
+					} else {
+						printTextClusterDescription(c, events, eventsErr)
+					}
+
+					return nil
+				}
+
+				return errors.New("cluster not found")
+			}
+
+			return errors.New("grid not found")
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
+
+	cmd.MarkFlagRequired("grid")
+
+	return cmd
+}
+
+// redactClusterConfig returns a copy of the cluster config without the kubeconfig, which
+// includes credentials, or the admin console password
+func redactClusterConfig(c *types.ClusterConfig) *types.ClusterConfig {
+	redactedConfig := *c
+	if redactedConfig.Kubeconfig != "" {
+		redactedConfig.Kubeconfig = redacted
+	}
+	if c.AdminConsole != nil {
+		adminConsole := *c.AdminConsole
+		if adminConsole.SharedPassword != "" {
+			adminConsole.SharedPassword = redacted
+		}
+		redactedConfig.AdminConsole = &adminConsole
+	}
+
+	return &redactedConfig
+}
+
+// clusterEndpoint returns the API server address of the cluster
+func clusterEndpoint(c *types.ClusterConfig) string {
+	if c.EKS != nil && c.EKS.Endpoint != "" {
+		return c.EKS.Endpoint
+	}
+	if c.Kubeconfig == "" {
+		return ""
+	}
+
+	kubeconfig, err := clientcmd.Load([]byte(c.Kubeconfig))
+	if err != nil {
+		return ""
+	}
+	kubeContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return ""
+	}
+	kubeCluster, ok := kubeconfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return ""
+	}
+
+	return kubeCluster.Server
+}
+
+func printTextClusterDescription(c *types.ClusterConfig, events []corev1.Event, eventsErr error) {
+	fmt.Printf("Name: %s\n", c.Name)
+	fmt.Printf("Provider: %s\n", c.Provider)
+	fmt.Printf("Region: %s\n", c.Region)
+	fmt.Printf("Version: %s\n", valueOrNone(c.Version))
+	fmt.Printf("Existing: %t\n", c.IsExisting)
+	fmt.Printf("Phase: %s\n", c.GetPhase())
+	if c.Status != nil && c.Status.Message != "" {
+		fmt.Printf("Message: %s\n", c.Status.Message)
+	}
+	if c.CreatedAt != nil {
+		fmt.Printf("Created: %s\n", c.CreatedAt.Format(time.RFC3339))
+	}
+	fmt.Printf("Endpoint: %s\n", valueOrNone(clusterEndpoint(c)))
+	if c.Kubeconfig != "" {
+		fmt.Printf("Kubeconfig: %s\n", redacted)
+	}
+
+	if c.EKS != nil {
+		fmt.Printf("VPC: %s\n", valueOrNone(c.EKS.VPCID))
+		fmt.Printf("Subnets: %s\n", valueOrNone(strings.Join(c.EKS.SubnetIDs, ", ")))
+		fmt.Printf("Security Groups: %s\n", valueOrNone(strings.Join(c.EKS.SecurityGroupIDs, ", ")))
+		fmt.Printf("Node Groups: %s\n", valueOrNone(strings.Join(c.EKS.NodeGroups, ", ")))
+	}
+
+	if c.Status != nil && len(c.Status.Conditions) > 0 {
+		fmt.Printf("Conditions:\n")
+		for _, condition := range c.Status.Conditions {
+			fmt.Printf("  - %s=%s (%s)", condition.Type, condition.Status, condition.LastTransitionTime.Format(time.RFC3339))
+			if condition.Message != "" {
+				fmt.Printf(": %s", condition.Message)
+			}
+			fmt.Printf("\n")
+		}
+	}
+
+	fmt.Printf("Applications:\n")
+	if len(c.Applications) == 0 {
+		fmt.Printf("  <none>\n")
+	}
+	for _, a := range c.Applications {
+		fmt.Printf("  - %s (deployed %s)\n", a.Name, a.DeployedAt.Format(time.RFC3339))
+	}
+	if c.AdminConsole != nil {
+		password := redacted
+		if c.AdminConsole.SharedPassword == "" {
+			password = "(set in the application spec)"
+		}
+		fmt.Printf("Admin Console:\n  Namespace: %s\n  Password: %s\n", c.AdminConsole.Namespace, password)
+	}
+
+	fmt.Printf("Recent Events:\n")
+	if eventsErr != nil {
+		fmt.Printf("  unable to list events: %s\n", eventsErr.Error())
+		return
+	}
+	if len(events) == 0 {
+		fmt.Printf("  <none>\n")
+	}
+	for _, event := range events {
+		fmt.Printf("  %s  %s  %s/%s  %s: %s\n",
+			cluster.EventTime(event).Format(time.RFC3339), event.Type,
+			event.Namespace, event.InvolvedObject.Name, event.Reason, strings.TrimSpace(event.Message))
+	}
+}
+
+func printJSONClusterDescription(c *types.ClusterConfig, events []corev1.Event) {
+	description := struct {
+		*types.ClusterConfig
+		Endpoint string         `json:"endpoint,omitempty"`
+		Events   []corev1.Event `json:"events,omitempty"`
+	}{
+		ClusterConfig: redactClusterConfig(c),
+		Endpoint:      clusterEndpoint(c),
+		Events:        events,
+	}
+
+	str, _ := json.MarshalIndent(description, "", "    ")
+	fmt.Println(string(str))
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
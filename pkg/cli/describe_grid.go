@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/replicatedhq/kubectl-grid/pkg/grid"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
@@ -47,17 +48,31 @@ func DescribeGridCmd() *cobra.Command {
 func printTextGridDescription(g *types.GridConfig) {
 	clusters := []string{}
 	for _, c := range g.ClusterConfigs {
-		renderedCluster := fmt.Sprintf("  - Name: %s\n    Provider: %s\n",
-			c.Name, c.Provider)
+		renderedCluster := fmt.Sprintf("  - Name: %s\n    Provider: %s\n    Phase: %s\n",
+			c.Name, c.Provider, c.GetPhase())
+		if c.Status != nil && c.Status.Message != "" {
+			renderedCluster += fmt.Sprintf("    Message: %s\n", c.Status.Message)
+		}
+		if c.Status != nil && len(c.Status.Conditions) > 0 {
+			renderedCluster += "    Conditions:\n"
+			for _, condition := range c.Status.Conditions {
+				renderedCluster += fmt.Sprintf("      - %s=%s (%s)", condition.Type, condition.Status, condition.LastTransitionTime.Format(time.RFC3339))
+				if condition.Message != "" {
+					renderedCluster += fmt.Sprintf(": %s", condition.Message)
+				}
+				renderedCluster += "\n"
+			}
+		}
 
 		clusters = append(clusters, renderedCluster)
 	}
 
 	fmt.Printf(`Grid Name: %s
+Phase: %s
 Clusters:
 %s	
 `,
-		g.Name, strings.Join(clusters, "\n"))
+		g.Name, gridPhase(g), strings.Join(clusters, "\n"))
 }
 
 func printJSONGridDescription(g *types.GridConfig) {
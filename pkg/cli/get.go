@@ -26,7 +26,9 @@ func GetCmd() *cobra.Command {
 	cmd.PersistentFlags().StringP("output", "o", "", "Output format (empty or json)")
 
 	cmd.AddCommand(GetGridsCmd())
+	cmd.AddCommand(GetClustersCmd())
 	cmd.AddCommand(GetNamespacesCmd())
+	cmd.AddCommand(GetPreflightsCmd())
 
 	return cmd
 }
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/print"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func GetClustersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "clusters",
+		Aliases: []string{
+			"cluster",
+		},
+		Short:         "List the clusters in a grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return err
+			}
+
+			for _, g := range grids {
+				if g.Name == v.GetString("grid") {
+					if v.GetString("output") == "json" {
+						printClustersJSON(g)
+					} else {
+						printClustersTable(cmd.Context(), g)
+					}
+
+					return nil
+				}
+			}
+
+			return errors.New("grid not found")
+		},
+	}
+
+	return cmd
+}
+
+func printClustersJSON(g *types.GridConfig) {
+	clusters := []*types.ClusterConfig{}
+	for _, c := range g.ClusterConfigs {
+		clusters = append(clusters, redactClusterConfig(c))
+	}
+
+	str, _ := json.MarshalIndent(clusters, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printClustersTable(ctx context.Context, g *types.GridConfig) {
+	if len(g.ClusterConfigs) == 0 {
+		fmt.Println("No clusters found")
+		return
+	}
+
+	nodes := getReadyNodeCounts(ctx, g)
+
+	w := print.NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "NAME", "PROVIDER", "REGION", "VERSION", "EXISTING", "NODES", "PHASE", "AGE")
+	for _, c := range g.ClusterConfigs {
+		version := c.Version
+		if version == "" {
+			version = "-"
+		}
+		age := "-"
+		if c.CreatedAt != nil {
+			age = formatAge(time.Since(*c.CreatedAt))
+		}
+
+		fmt.Fprintf(w, fmtColumns, c.Name, c.Provider, c.Region, version, strconv.FormatBool(c.IsExisting), nodes[c.Name], c.GetPhase(), age)
+	}
+}
+
+// getReadyNodeCounts returns the number of ready nodes out of the total for each cluster,
+// for example "2/3". Clusters that are not ready or can't be reached are "-"
+func getReadyNodeCounts(ctx context.Context, g *types.GridConfig) map[string]string {
+	mu := sync.Mutex{}
+	counts := map[string]string{}
+
+	tasks := []orchestrator.Task{}
+	for _, c := range g.ClusterConfigs {
+		counts[c.Name] = "-"
+		if c.GetPhase() != types.ClusterPhaseReady || c.Kubeconfig == "" {
+			continue
+		}
+
+		c := c
+		tasks = append(tasks, orchestrator.Task{
+			Cluster: c.Name,
+			Run: func(ctx context.Context) error {
+				nodes, err := kubectl.GetNodes(ctx, c)
+				if err != nil {
+					return err
+				}
+
+				ready := 0
+				for _, node := range nodes.Items {
+					if node.IsReady() {
+						ready++
+					}
+				}
+
+				mu.Lock()
+				counts[c.Name] = fmt.Sprintf("%d/%d", ready, len(nodes.Items))
+				mu.Unlock()
+				return nil
+			},
+		})
+	}
+
+	// a cluster that can't be reached is shown without a node count
+	orchestrator.Run(ctx, tasks, orchestrator.Options{})
+
+	return counts
+}
+
+// formatAge formats a duration the way kubectl shows the age of a resource
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
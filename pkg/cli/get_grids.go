@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/replicatedhq/kubectl-grid/pkg/grid"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
@@ -57,9 +58,41 @@ func printGridsTable(grids []*types.GridConfig) {
 	w := print.NewTabWriter()
 	defer w.Flush()
 
-	fmtColumns := "%s\n"
-	fmt.Fprintf(w, fmtColumns, "NAME")
+	fmtColumns := "%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "NAME", "PHASE", "CLUSTERS")
 	for _, g := range grids {
-		fmt.Fprintf(w, fmtColumns, g.Name)
+		fmt.Fprintf(w, fmtColumns, g.Name, gridPhase(g), clusterPhaseSummary(g))
 	}
 }
+
+// gridPhase returns the phase of the grid. Grids that were created before the phase was
+// recorded are ready
+func gridPhase(g *types.GridConfig) string {
+	if g.Phase == "" {
+		return types.GridPhaseReady
+	}
+	return g.Phase
+}
+
+// clusterPhaseSummary returns the number of clusters in each phase, for example "2 Ready, 1 Provisioning"
+func clusterPhaseSummary(g *types.GridConfig) string {
+	if len(g.ClusterConfigs) == 0 {
+		return "0"
+	}
+
+	phases := []string{}
+	counts := map[string]int{}
+	for _, c := range g.ClusterConfigs {
+		phase := c.GetPhase()
+		if _, ok := counts[phase]; !ok {
+			phases = append(phases, phase)
+		}
+		counts[phase]++
+	}
+
+	summary := []string{}
+	for _, phase := range phases {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[phase], phase))
+	}
+	return strings.Join(summary, ", ")
+}
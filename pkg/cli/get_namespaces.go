@@ -35,9 +35,9 @@ func GetNamespacesCmd() *cobra.Command {
 
 			for _, g := range grids {
 				if g.Name == v.GetString("grid") {
-					for _, c := range g.ClusterConfigs {
+					for _, c := range g.ReadyClusters() {
 						if c.Name == v.GetString("cluster") {
-							namespaces, err := cluster.ListNamespaces(c)
+							namespaces, err := cluster.ListNamespaces(cmd.Context(), c)
 							if err != nil {
 								return err
 							}
@@ -52,7 +52,7 @@ func GetNamespacesCmd() *cobra.Command {
 						}
 					}
 
-					return errors.New("cluster not found")
+					return errors.New("ready cluster not found")
 				}
 			}
 
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func GetPreflightsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "preflights",
+		Aliases: []string{
+			"preflight",
+		},
+		Short:         "Show the preflight results for each cluster in the grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return err
+			}
+
+			for _, g := range grids {
+				if g.Name == v.GetString("grid") {
+					if v.GetString("output") == "json" {
+						printPreflightsJSON(g)
+					} else {
+						printPreflightMatrix(os.Stdout, g)
+					}
+
+					return nil
+				}
+			}
+
+			return errors.New("grid not found")
+		},
+	}
+
+	return cmd
+}
+
+func printPreflightsJSON(g *types.GridConfig) {
+	results := map[string][]types.PreflightResult{}
+	for _, c := range g.ReadyClusters() {
+		results[c.Name] = c.PreflightResults
+	}
+
+	str, _ := json.MarshalIndent(results, "", "    ")
+	fmt.Println(string(str))
+}
+
+// printPreflightMatrix prints a table with a row for each preflight check
+// and a column for each cluster
+func printPreflightMatrix(out io.Writer, g *types.GridConfig) {
+	printed := printClusterMatrix(out, g, "CHECK", func(c *types.ClusterConfig) []matrixResult {
+		results := []matrixResult{}
+		for _, r := range c.PreflightResults {
+			results = append(results, matrixResult{Row: r.Check, State: r.State})
+		}
+		return results
+	})
+	if !printed {
+		fmt.Fprintln(out, "No preflight results found")
+	}
+}
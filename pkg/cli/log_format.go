@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/spf13/viper"
+)
+
+// applyLogFormat will set the logger in loggerSpec from --log-format
+func applyLogFormat(v *viper.Viper, loggerSpec *types.LoggerSpec) error {
+	switch v.GetString("log-format") {
+	case "", "text":
+	case "json":
+		loggerSpec.JSON = &types.JSONLoggerSpec{}
+	default:
+		return errors.Errorf("unknown log format %q, must be text or json", v.GetString("log-format"))
+	}
+
+	if loggerSpec.JSON != nil && isWatching(v) {
+		return errors.New("--watch can't be used with json logs")
+	}
+
+	return nil
+}
+
+// messageOutput returns where the output that isn't a progress log is printed. When the
+// logs are json, stdout only has the logs so that it can be parsed
+func messageOutput(loggerSpec types.LoggerSpec) io.Writer {
+	if loggerSpec.JSON != nil {
+		return os.Stderr
+	}
+	return os.Stdout
+}
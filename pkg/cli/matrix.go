@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/print"
+)
+
+// matrixResult is the state of a row of a matrix on one cluster
+type matrixResult struct {
+	Row   string
+	State string
+}
+
+// printClusterMatrix prints a table with a row for each result and a column for each
+// ready cluster, with the rows in the order they are first seen. false is returned
+// without printing anything when no cluster has results
+func printClusterMatrix(out io.Writer, g *types.GridConfig, rowHeader string, clusterResults func(c *types.ClusterConfig) []matrixResult) bool {
+	clusters := g.ReadyClusters()
+
+	rows := []string{}
+	states := map[string]map[string]string{}
+	for _, c := range clusters {
+		for _, r := range clusterResults(c) {
+			if _, ok := states[r.Row]; !ok {
+				rows = append(rows, r.Row)
+				states[r.Row] = map[string]string{}
+			}
+			states[r.Row][c.Name] = r.State
+		}
+	}
+
+	if len(rows) == 0 {
+		return false
+	}
+
+	w := print.NewTabWriterTo(out)
+	defer w.Flush()
+
+	header := []string{rowHeader}
+	for _, c := range clusters {
+		header = append(header, strings.ToUpper(c.Name))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		line := []string{row}
+		for _, c := range clusters {
+			state, ok := states[row][c.Name]
+			if !ok {
+				state = "-"
+			}
+			line = append(line, state)
+		}
+		fmt.Fprintln(w, strings.Join(line, "\t"))
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/report"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "report",
+		Short:         "Generate a report of a run on a grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if v.GetString("run") == "" {
+				return errors.New("run is required")
+			}
+
+			r, err := runs.Get(runs.Dir(v.GetString("config-file")), v.GetString("run"))
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if v.GetString("output-file") != "" {
+				f, err := os.Create(v.GetString("output-file"))
+				if err != nil {
+					return errors.Wrap(err, "failed to create output file")
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if err := report.Generate(r, v.GetString("format"), w); err != nil {
+				return errors.Wrap(err, "failed to generate report")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("run", "", "ID of the run to report on")
+	cmd.Flags().String("format", report.FormatHTML, "Report format (html or markdown)")
+	cmd.Flags().String("output-file", "", "Write the report to this file instead of stdout")
+
+	return cmd
+}
@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -37,11 +40,19 @@ func RootCmd() *cobra.Command {
 	KubernetesConfigFlags.AddFlags(cmd.Flags())
 
 	cmd.PersistentFlags().String("config-file", filepath.Join(homeDir(), ".grid", "config"), "Path to the grid config file to store current grids")
+	cmd.PersistentFlags().String("log-format", "text", "Format of the progress logs, text or json")
+	addTracingFlags(cmd)
 
 	cmd.AddCommand(CreateCmd())
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(DescribeCmd())
 	cmd.AddCommand(DeployCmd())
+	cmd.AddCommand(UndeployCmd())
+	cmd.AddCommand(AdminConsoleCmd())
+	cmd.AddCommand(SupportBundleCmd())
+	cmd.AddCommand(RunCmd())
+	cmd.AddCommand(RunsCmd())
+	cmd.AddCommand(ReportCmd())
 	cmd.AddCommand(DeleteCmd())
 
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
@@ -49,8 +60,23 @@ func RootCmd() *cobra.Command {
 }
 
 func InitAndExecute() {
-	if err := RootCmd().Execute(); err != nil {
-		fmt.Println(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first interrupt cancels the running command so that it can record what it created,
+	// a second interrupt exits immediately
+	signalCh := make(chan os.Signal, 2)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalCh
+		fmt.Fprintln(os.Stderr, "\nInterrupted, stopping. Interrupt again to exit immediately")
+		cancel()
+		<-signalCh
+		os.Exit(130)
+	}()
+
+	if err := RootCmd().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
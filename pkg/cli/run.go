@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/experiment"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/print"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+func RunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "run",
+		Short:         "Run an experiment on all clusters in a grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			data, err := ioutil.ReadFile(v.GetString("experiment"))
+			if err != nil {
+				return errors.Wrap(err, "failed to read experiment file")
+			}
+
+			e := types.Experiment{}
+			if err := yaml.Unmarshal(data, &e); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal %s", v.GetString("experiment"))
+			}
+
+			// experiments don't log progress events, so with json logs stdout is left empty
+			loggerSpec := types.LoggerSpec{}
+			if err := applyLogFormat(v, &loggerSpec); err != nil {
+				return err
+			}
+			out := messageOutput(loggerSpec)
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return errors.Wrap(err, "failed to list grids")
+			}
+
+			for _, g := range grids {
+				if g.Name == v.GetString("grid") {
+					r := runs.NewRun(runs.KindExperiment, g.Name, e.Name, data)
+					ctx, stopWatch := startWatch(watch.WithOutput(cmd.Context(), out), isWatching(v))
+					results, err := experiment.Run(ctx, g, &e, experiment.RunOptions{
+						OutputDir:      v.GetString("output-dir"),
+						Out:            out,
+						NodeTerminator: grid.EKSNodeTerminator{},
+					})
+					stopWatch()
+					r.FinishedAt = time.Now()
+					printExperimentResults(out, results)
+
+					if recordErr := recordExperimentRun(runs.Dir(v.GetString("config-file")), r, g, results); recordErr != nil {
+						return errors.Wrap(recordErr, "failed to record run")
+					}
+					fmt.Fprintf(out, "\nRecorded run %s\n", r.ID)
+
+					if e.Spec.Job != nil && e.Spec.Job.JUnit != nil {
+						reportPath, mergeErr := experiment.MergeJUnitResults(v.GetString("output-dir"), g.Name, results)
+						if mergeErr != nil {
+							return errors.Wrap(mergeErr, "failed to merge junit results")
+						}
+						fmt.Fprintf(out, "\nJUnit report written to %s\n", reportPath)
+					}
+
+					if err != nil {
+						return err
+					}
+
+					return nil
+				}
+			}
+
+			return errors.New("unable to find grid")
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
+	cmd.Flags().String("experiment", "", "Path to YAML manifest describing the experiment to run")
+	cmd.Flags().String("output-dir", "results", "Directory to write results to, in a subdirectory named by grid")
+	addWatchFlags(cmd)
+
+	return cmd
+}
+
+func recordExperimentRun(runsDir string, r *runs.Run, g *types.GridConfig, results []*experiment.ClusterResult) error {
+	for _, result := range results {
+		for _, c := range g.ClusterConfigs {
+			if c.Name != result.ClusterName {
+				continue
+			}
+
+			clusterRun := runs.NewClusterRun(c)
+			clusterRun.SetError(result.Error)
+			clusterRun.StartedAt = result.StartedAt
+			clusterRun.FinishedAt = result.FinishedAt
+			clusterRun.Steps = append(clusterRun.Steps, types.StepResult{
+				Name:       types.StepExperiment,
+				StartedAt:  result.StartedAt,
+				FinishedAt: result.FinishedAt,
+				Error:      result.Error,
+			})
+
+			if result.LogsPath != "" {
+				logs, err := ioutil.ReadFile(result.LogsPath)
+				if err != nil {
+					return errors.Wrapf(err, "failed to read logs for cluster %s", c.Name)
+				}
+				if err := runs.WriteClusterLogs(runsDir, r, clusterRun, logs); err != nil {
+					return errors.Wrapf(err, "failed to write logs for cluster %s", c.Name)
+				}
+			}
+			if result.ResultsPath != "" {
+				if err := runs.AddArtifact(runsDir, r, clusterRun, result.ResultsPath); err != nil {
+					return errors.Wrapf(err, "failed to add results for cluster %s", c.Name)
+				}
+			}
+
+			r.Clusters = append(r.Clusters, clusterRun)
+		}
+	}
+
+	return runs.Save(runsDir, r)
+}
+
+func printExperimentResults(out io.Writer, results []*experiment.ClusterResult) {
+	w := print.NewTabWriterTo(out)
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "CLUSTER", "RESULTS", "LOGS", "ERROR")
+	for _, r := range results {
+		fmt.Fprintf(w, fmtColumns, r.ClusterName, r.ResultsPath, r.LogsPath, r.Error)
+	}
+}
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/print"
+	"github.com/replicatedhq/kubectl-grid/pkg/report"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func RunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "runs",
+		Short:         "List and inspect past deploys and experiments",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format (empty or json)")
+
+	cmd.AddCommand(RunsListCmd())
+	cmd.AddCommand(RunsDescribeCmd())
+	cmd.AddCommand(RunsLogsCmd())
+	cmd.AddCommand(RunsDiffCmd())
+
+	return cmd
+}
+
+func RunsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "list",
+		Aliases: []string{
+			"ls",
+		},
+		Short:         "List runs, most recent first",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			allRuns, err := runs.List(runs.Dir(v.GetString("config-file")))
+			if err != nil {
+				return errors.Wrap(err, "failed to list runs")
+			}
+
+			filtered := []*runs.Run{}
+			for _, r := range allRuns {
+				if v.GetString("grid") != "" && r.Grid != v.GetString("grid") {
+					continue
+				}
+				filtered = append(filtered, r)
+			}
+
+			if v.GetString("output") == "json" {
+				printRunsJSON(filtered)
+			} else {
+				printRunsTable(filtered)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Only list runs on this grid")
+
+	return cmd
+}
+
+func RunsDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "describe [run id]",
+		Short:         "Describe the result of a run on each cluster",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			r, err := runs.Get(runs.Dir(v.GetString("config-file")), args[0])
+			if err != nil {
+				return err
+			}
+
+			if v.GetString("output") == "json" {
+				printRunsJSON(r)
+			} else {
+				printTextRunDescription(r)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func RunsLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "logs [run id]",
+		Short:         "Print the logs collected from each cluster in a run",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			r, err := runs.Get(runs.Dir(v.GetString("config-file")), args[0])
+			if err != nil {
+				return err
+			}
+
+			found := false
+			for _, c := range r.Clusters {
+				if v.GetString("cluster") != "" && c.Name != v.GetString("cluster") {
+					continue
+				}
+				found = true
+
+				if c.LogsPath == "" {
+					fmt.Printf("# cluster: %s (no logs collected)\n", c.Name)
+					continue
+				}
+
+				logs, err := ioutil.ReadFile(c.LogsPath)
+				if err != nil {
+					return errors.Wrapf(err, "failed to read logs for cluster %s", c.Name)
+				}
+				fmt.Printf("# cluster: %s\n%s", c.Name, logs)
+			}
+
+			if !found {
+				return errors.New("unable to find cluster")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", "", "Only print logs from this cluster")
+
+	return cmd
+}
+
+func RunsDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "diff [run id] [run id]",
+		Short:         "Compare the results of the second run against the first, and fail on regressions",
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			runsDir := runs.Dir(v.GetString("config-file"))
+			a, err := runs.Get(runsDir, args[0])
+			if err != nil {
+				return err
+			}
+			b, err := runs.Get(runsDir, args[1])
+			if err != nil {
+				return err
+			}
+
+			thresholds, err := report.ParseThresholds(v.GetStringSlice("threshold"))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse thresholds")
+			}
+
+			changes, err := report.Diff(a, b, thresholds)
+			if err != nil {
+				return errors.Wrap(err, "failed to diff runs")
+			}
+
+			if v.GetString("output") == "json" {
+				printRunsJSON(changes)
+			} else {
+				if a.SpecHash != b.SpecHash {
+					fmt.Printf("Spec changed from %s to %s\n\n", a.SpecHash, b.SpecHash)
+				}
+				printRunsDiffTable(changes)
+			}
+
+			if regressions := report.Regressions(changes); regressions > 0 {
+				return errors.Errorf("found %d regressions", regressions)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("threshold", []string{}, "Percent increase allowed before a change is a regression, in the form readiness=50% or <k6 metric>.<stat>=20%, for example http_req_duration.p(95)=20%")
+
+	return cmd
+}
+
+func printRunsDiffTable(changes []report.Change) {
+	w := print.NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "CLUSTER", "CHECK", "BEFORE", "AFTER", "CHANGE", "REGRESSION")
+	for _, change := range changes {
+		regression := ""
+		if change.Regression {
+			regression = "yes"
+		}
+		fmt.Fprintf(w, fmtColumns, change.Cluster, change.Check, change.Before, change.After, change.Change, regression)
+	}
+}
+
+func printRunsJSON(v interface{}) {
+	str, _ := json.MarshalIndent(v, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printRunsTable(allRuns []*runs.Run) {
+	if len(allRuns) == 0 {
+		fmt.Println("No runs found")
+		return
+	}
+
+	w := print.NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "ID", "KIND", "GRID", "NAME", "SPEC", "STARTED", "DURATION", "OUTCOME")
+	for _, r := range allRuns {
+		fmt.Fprintf(w, fmtColumns, r.ID, r.Kind, r.Grid, r.Name, r.SpecHash,
+			r.StartedAt.Local().Format(time.RFC3339), runDuration(r.StartedAt, r.FinishedAt), r.Outcome())
+	}
+}
+
+func printTextRunDescription(r *runs.Run) {
+	fmt.Printf("ID: %s\nKind: %s\nGrid: %s\nName: %s\nSpec: %s\nStarted: %s\nDuration: %s\nOutcome: %s\nClusters:\n",
+		r.ID, r.Kind, r.Grid, r.Name, r.SpecHash, r.StartedAt.Local().Format(time.RFC3339), runDuration(r.StartedAt, r.FinishedAt), r.Outcome())
+
+	for _, c := range r.Clusters {
+		fmt.Printf("  - Name: %s\n    Provider: %s\n    Region: %s\n    Outcome: %s\n    Duration: %s\n",
+			c.Name, c.Provider, c.Region, c.Outcome, runDuration(c.StartedAt, c.FinishedAt))
+		if c.Error != "" {
+			fmt.Printf("    Error: %s\n", c.Error)
+		}
+		if len(c.PreflightResults) > 0 {
+			fmt.Printf("    Preflights:\n")
+			for _, p := range c.PreflightResults {
+				fmt.Printf("      - %s: %s\n", p.Check, p.State)
+			}
+		}
+		if c.LogsPath != "" {
+			fmt.Printf("    Logs: %s\n", c.LogsPath)
+		}
+		if len(c.Artifacts) > 0 {
+			fmt.Printf("    Artifacts:\n")
+			for _, artifact := range c.Artifacts {
+				fmt.Printf("      - %s\n", artifact)
+			}
+		}
+	}
+}
+
+func runDuration(startedAt time.Time, finishedAt time.Time) string {
+	if startedAt.IsZero() || finishedAt.IsZero() {
+		return ""
+	}
+
+	return finishedAt.Sub(startedAt).Round(time.Second).String()
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+func SupportBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "support-bundle",
+		Short:         "Collect support bundles from the clusters in a grid",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if v.GetString("spec") != "" && v.GetString("from-app") != "" {
+				return errors.New("only one of spec and from-app can be set")
+			}
+
+			opts := app.SupportBundleOptions{
+				ClusterName: v.GetString("cluster"),
+				SpecPath:    v.GetString("spec"),
+				OutputDir:   v.GetString("output-dir"),
+			}
+
+			if v.GetString("from-app") != "" {
+				data, err := ioutil.ReadFile(v.GetString("from-app"))
+				if err != nil {
+					return errors.Wrap(err, "failed to read app spec file")
+				}
+
+				application := types.Application{}
+				if err := yaml.Unmarshal(data, &application); err != nil {
+					return errors.Wrap(err, "failed to unmarshal app spec")
+				}
+				opts.Application = &application
+			}
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return errors.Wrap(err, "failed to list grids")
+			}
+
+			for _, g := range grids {
+				if g.Name == v.GetString("grid") {
+					bundlePaths, err := app.CollectSupportBundles(cmd.Context(), g, opts)
+					for _, bundlePath := range bundlePaths {
+						fmt.Println(bundlePath)
+					}
+					if err != nil {
+						return err
+					}
+
+					return nil
+				}
+			}
+
+			return errors.New("unable to find grid")
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
+	cmd.Flags().StringP("cluster", "c", "", "Name of a single cluster to collect from, defaults to all clusters in the grid")
+	cmd.Flags().String("spec", "", "Path or URL of the support bundle spec to use")
+	cmd.Flags().String("from-app", "", "Path to YAML manifest describing a deployed application to use the support bundle spec from")
+	cmd.Flags().String("output-dir", "support-bundles", "Directory to write support bundles to, in a subdirectory named by grid")
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tracingShutdownTimeout is how long to wait for the spans to be exported before exiting
+const tracingShutdownTimeout = 10 * time.Second
+
+func addTracingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("trace-endpoint", "", "host:port of an OTLP gRPC collector to export traces of create, delete and deploy to")
+	cmd.PersistentFlags().Bool("trace-insecure", false, "Connect to the trace endpoint without TLS")
+	cmd.PersistentFlags().String("trace-file", "", "Path to write traces of create, delete and deploy to as JSON lines, instead of a collector")
+}
+
+// startTracing will export the spans of the operations that are run after it returns, when
+// a trace endpoint or file is set. stop must be called to export the spans before exiting
+func startTracing(ctx context.Context, v *viper.Viper) (func(), error) {
+	shutdown, err := tracing.Init(ctx, tracing.Options{
+		Endpoint: v.GetString("trace-endpoint"),
+		Insecure: v.GetBool("trace-insecure"),
+		File:     v.GetString("trace-file"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start tracing")
+	}
+
+	return func() {
+		// the command's context may have been cancelled by an interrupt, and the spans
+		// from the interrupted operation should still be exported
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdown(shutdownCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export traces: %s\n", err.Error())
+		}
+	}, nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+func UndeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "undeploy",
+		Short:         "Remove an application from a grid without deleting the clusters",
+		SilenceErrors: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			data, err := ioutil.ReadFile(v.GetString("app"))
+			if err != nil {
+				return errors.Wrap(err, "failed to read app spec file")
+			}
+
+			application := types.Application{}
+			if err := yaml.Unmarshal(data, &application); err != nil {
+				return errors.Wrap(err, "failed to unmarshal app spec")
+			}
+
+			grids, err := grid.List(v.GetString("config-file"))
+			if err != nil {
+				return errors.Wrap(err, "failed to list grids")
+			}
+
+			// one reader for every cluster, so that answers piped to stdin aren't lost in the buffer
+			in := bufio.NewReader(os.Stdin)
+			confirm := func(c *types.ClusterConfig) bool {
+				return confirmUndeploy(in, c)
+			}
+			if v.GetBool("yes") {
+				confirm = nil
+			}
+
+			for _, g := range grids {
+				if g.Name == v.GetString("grid") {
+					undeployErr := app.Undeploy(cmd.Context(), g, &application, confirm)
+
+					// the clusters the app was removed from are recorded, even if a later cluster failed
+					if err := grid.Update(v.GetString("config-file"), g); err != nil {
+						return errors.Wrap(err, "failed to update grid config")
+					}
+
+					if undeployErr != nil {
+						return errors.Wrap(undeployErr, "failed to undeploy app")
+					}
+
+					return nil
+				}
+			}
+
+			return errors.New("unable to find grid")
+		},
+	}
+
+	cmd.Flags().StringP("grid", "g", "", "Name of the grid")
+	cmd.Flags().String("app", "", "Path to YAML manifest describing the application to remove")
+	cmd.Flags().BoolP("yes", "y", false, "Remove the application from every cluster without asking for confirmation")
+
+	return cmd
+}
+
+func confirmUndeploy(in *bufio.Reader, c *types.ClusterConfig) bool {
+	fmt.Printf("Remove application from cluster %s? [y/N]: ", c.Name)
+
+	answer, err := in.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
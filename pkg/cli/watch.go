@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("watch", false, "Show the progress on each cluster in a live table, or as lines prefixed with the cluster name when stdout is not a terminal")
+	cmd.Flags().Bool("tui", false, "Alias for --watch")
+}
+
+func isWatching(v *viper.Viper) bool {
+	return v.GetBool("watch") || v.GetBool("tui")
+}
+
+// startWatch returns a context that shows the progress of the tasks run with it, when
+// watching. stop must be called before anything else is printed
+func startWatch(ctx context.Context, watching bool) (context.Context, func()) {
+	if !watching {
+		return ctx, func() {}
+	}
+
+	t := watch.NewTable(os.Stdout, isatty.IsTerminal(os.Stdout.Fd()))
+	t.Start()
+	return watch.NewContext(ctx, t), t.Stop
+}
@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// GetRESTConfig will return a client-go config for the cluster
+func GetRESTConfig(clusterConfig *types.ClusterConfig) (*rest.Config, error) {
+	tmp, err := ioutil.TempFile("", "grid")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.RemoveAll(tmp.Name())
+
+	if err := ioutil.WriteFile(tmp.Name(), []byte(clusterConfig.Kubeconfig), 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to create kubeconfig file")
+	}
+	cfg, err := clientcmd.BuildConfigFromFlags("", tmp.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build client-go config")
+	}
+
+	return cfg, nil
+}
+
+// GetClientset will return a clientset for the cluster
+func GetClientset(clusterConfig *types.ClusterConfig) (*kubernetes.Clientset, error) {
+	cfg, err := GetRESTConfig(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get rest config")
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create clientset")
+	}
+
+	return clientset, nil
+}
@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListRecentEvents returns the most recent events in all namespaces, newest first
+func ListRecentEvents(ctx context.Context, clusterConfig *types.ClusterConfig, limit int) ([]corev1.Event, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	events, err := clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list events")
+	}
+
+	items := events.Items
+	sort.Slice(items, func(i, j int) bool {
+		return EventTime(items[i]).After(EventTime(items[j]))
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+// EventTime returns the last time the event was seen
+func EventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -2,38 +2,20 @@ package cluster
 
 import (
 	"context"
-	"io/ioutil"
-	"os"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
-func ListNamespaces(clusterConfig *types.ClusterConfig) (*corev1.NamespaceList, error) {
-	tmp, err := ioutil.TempFile("", "grid")
+func ListNamespaces(ctx context.Context, clusterConfig *types.ClusterConfig) (*corev1.NamespaceList, error) {
+	clientset, err := GetClientset(clusterConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create temp file")
+		return nil, errors.Wrap(err, "failed to get clientset")
 	}
-	defer os.RemoveAll(tmp.Name())
 
-	if err := ioutil.WriteFile(tmp.Name(), []byte(clusterConfig.Kubeconfig), 0644); err != nil {
-		return nil, errors.Wrap(err, "failed to create kubeconfig file")
-	}
-	cfg, err := clientcmd.BuildConfigFromFlags("", tmp.Name())
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to build client-go config")
-	}
-
-	clientset, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create clientset")
-	}
-
-	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list namespapces")
 	}
@@ -0,0 +1,35 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListCrashLoopingPods returns the names of pods in the namespace with a container in CrashLoopBackOff
+func ListCrashLoopingPods(ctx context.Context, clusterConfig *types.ClusterConfig, namespace string) ([]string, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	crashLooping := []string{}
+	for _, pod := range pods.Items {
+		containerStatuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, containerStatus := range containerStatuses {
+			if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == "CrashLoopBackOff" {
+				crashLooping = append(crashLooping, pod.Name)
+				break
+			}
+		}
+	}
+
+	return crashLooping, nil
+}
@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetSecretValue will return the value of a single key in a secret
+func GetSecretValue(ctx context.Context, clusterConfig *types.ClusterConfig, namespace string, name string, key string) (string, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get clientset")
+	}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get secret")
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", errors.Errorf("key %s not found in secret %s", key, name)
+	}
+
+	return string(value), nil
+}
+
+// ProxyServiceGet will make a GET request to the service through the api server proxy.
+// service is the name and port of the service, for example kotsadm:3000
+func ProxyServiceGet(ctx context.Context, clusterConfig *types.ClusterConfig, namespace string, service string, path string, headers map[string]string) ([]byte, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	req := clientset.CoreV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("services").
+		Name(service).
+		SubResource("proxy").
+		Suffix(path)
+	for k, v := range headers {
+		req = req.SetHeader(k, v)
+	}
+
+	b, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to proxy request")
+	}
+
+	return b, nil
+}
+
+// ProxyServiceGetWithStatus is ProxyServiceGet, but returns the status code and body
+// instead of an error when the service responds with an error status
+func ProxyServiceGetWithStatus(ctx context.Context, clusterConfig *types.ClusterConfig, namespace string, service string, path string) (int, []byte, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	result := clientset.CoreV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("services").
+		Name(service).
+		SubResource("proxy").
+		Suffix(path).
+		Do(ctx)
+
+	statusCode := 0
+	result.StatusCode(&statusCode)
+	b, err := result.Raw()
+	if err != nil && statusCode == 0 {
+		return 0, nil, errors.Wrap(err, "failed to proxy request")
+	}
+
+	return statusCode, b, nil
+}
+
+// GetReadyEndpointsCount returns the number of ready addresses for the service
+func GetReadyEndpointsCount(ctx context.Context, clusterConfig *types.ClusterConfig, namespace string, service string) (int, error) {
+	clientset, err := GetClientset(clusterConfig)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get clientset")
+	}
+
+	endpoints, err := clientset.CoreV1().Endpoints(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get endpoints")
+	}
+
+	count := 0
+	for _, subset := range endpoints.Subsets {
+		count += len(subset.Addresses)
+	}
+
+	return count, nil
+}
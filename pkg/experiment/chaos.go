@@ -0,0 +1,326 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultChaosReadyTimeout = 10 * time.Minute
+	defaultScaleDownDuration = 30 * time.Second
+	nodeReplaceTimeout       = 20 * time.Minute
+	eksNodeGroupLabel        = "eks.amazonaws.com/nodegroup"
+)
+
+// NodeTerminator terminates the instance backing a node, so that its node group replaces it
+type NodeTerminator interface {
+	TerminateNode(ctx context.Context, c *types.ClusterConfig, instanceID string) error
+}
+
+type ChaosActionResult struct {
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	// RecoveredAfter is the time from the start of the action until the application was ready
+	RecoveredAfter string `json:"recoveredAfter,omitempty"`
+	Ready          bool   `json:"ready"`
+	Error          string `json:"error,omitempty"`
+}
+
+func runChaosExperiment(ctx context.Context, gridName string, c *types.ClusterConfig, e *types.Experiment, outputDir string, nodeTerminator NodeTerminator, out io.Writer) (string, []byte, error) {
+	chaosSpec := e.Spec.Chaos
+
+	data, err := ioutil.ReadFile(chaosSpec.Application)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read application file")
+	}
+	a := types.Application{}
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to unmarshal %s", chaosSpec.Application)
+	}
+
+	readyTimeout := defaultChaosReadyTimeout
+	if chaosSpec.ReadyTimeout != "" {
+		readyTimeout, err = time.ParseDuration(chaosSpec.ReadyTimeout)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to parse ready timeout")
+		}
+	}
+
+	var logs bytes.Buffer
+	w := io.MultiWriter(&logs, out)
+
+	results := []ChaosActionResult{}
+	recovered := true
+	for _, action := range chaosSpec.Actions {
+		result := runChaosAction(ctx, c, &a, action, readyTimeout, nodeTerminator, w)
+		if result.Ready {
+			fmt.Fprintf(w, "%s %s: application ready after %s\n", result.Action, result.Target, result.RecoveredAfter)
+		} else {
+			fmt.Fprintf(w, "%s %s: %s\n", result.Action, result.Target, result.Error)
+			recovered = false
+		}
+		results = append(results, result)
+	}
+
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", logs.Bytes(), errors.Wrap(err, "failed to marshal results")
+	}
+	resultsPath, err := writeResultsFile(outputDir, gridName, fmt.Sprintf("%s-chaos.json", c.Name), b)
+	if err != nil {
+		return "", logs.Bytes(), errors.Wrap(err, "failed to write chaos results")
+	}
+
+	if !recovered {
+		return resultsPath, logs.Bytes(), errors.New("application did not recover from one or more actions")
+	}
+
+	return resultsPath, logs.Bytes(), nil
+}
+
+// runChaosAction will run the action and wait for the application to be ready. when the
+// action fails, the application is not waited for
+func runChaosAction(ctx context.Context, c *types.ClusterConfig, a *types.Application, action types.ChaosAction, readyTimeout time.Duration, nodeTerminator NodeTerminator, out io.Writer) (result ChaosActionResult) {
+	result.StartedAt = time.Now()
+
+	// cleanup runs after the readiness check, so that the check happens while disrupted
+	var cleanup func() error
+	defer func() {
+		if cleanup != nil {
+			if err := cleanup(); err != nil && result.Error == "" {
+				result.Error = errors.Wrap(err, "failed to clean up").Error()
+			}
+		}
+	}()
+
+	var err error
+	switch {
+	case action.DrainNode != nil:
+		result.Action = "drainNode"
+		result.Target, err = drainRandomNode(ctx, c, action.DrainNode, readyTimeout)
+		if result.Target != "" {
+			nodeName := result.Target
+			cleanup = func() error {
+				// the node is uncordoned even when the experiment was cancelled
+				return kubectl.UncordonNode(context.Background(), c, nodeName)
+			}
+		}
+	case action.DeletePods != nil:
+		result.Action = "deletePods"
+		result.Target = fmt.Sprintf("%s/%s", action.DeletePods.Namespace, action.DeletePods.Selector)
+		err = kubectl.DeletePods(ctx, c, action.DeletePods.Namespace, action.DeletePods.Selector)
+	case action.ScaleDeployment != nil:
+		result.Action = "scaleDeployment"
+		result.Target = fmt.Sprintf("%s/%s", action.ScaleDeployment.Namespace, action.ScaleDeployment.Name)
+		err = scaleDeploymentDownAndUp(ctx, c, action.ScaleDeployment, readyTimeout)
+	case action.RestartNodeGroup != nil:
+		result.Action = "restartNodeGroup"
+		result.Target, err = restartNodeGroup(ctx, c, action.RestartNodeGroup, nodeTerminator, out)
+	default:
+		result.Error = "no action specified"
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	fmt.Fprintf(out, "%s %s: waiting for application to be ready\n", result.Action, result.Target)
+
+	if err := app.WaitForApplicationReady(ctx, c, a, readyTimeout); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Ready = true
+	result.RecoveredAfter = time.Since(result.StartedAt).Round(time.Second).String()
+
+	return result
+}
+
+// drainRandomNode returns the name of the node that was drained
+func drainRandomNode(ctx context.Context, c *types.ClusterConfig, drainNode *types.DrainNodeAction, timeout time.Duration) (string, error) {
+	nodes, err := kubectl.GetNodes(ctx, c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes")
+	}
+
+	candidates := []kubectl.Node{}
+	for _, node := range nodes.Items {
+		if node.IsReady() && matchesSelector(node.Metadata.Labels, drainNode.Selector) {
+			candidates = append(candidates, node)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", errors.New("no ready nodes match the selector")
+	}
+
+	node := candidates[rand.New(rand.NewSource(time.Now().UnixNano())).Intn(len(candidates))]
+	if err := kubectl.DrainNode(ctx, c, node.Metadata.Name, timeout); err != nil {
+		return node.Metadata.Name, errors.Wrapf(err, "failed to drain node %s", node.Metadata.Name)
+	}
+
+	return node.Metadata.Name, nil
+}
+
+func scaleDeploymentDownAndUp(ctx context.Context, c *types.ClusterConfig, scaleDeployment *types.ScaleDeploymentAction, timeout time.Duration) error {
+	duration := defaultScaleDownDuration
+	if scaleDeployment.Duration != "" {
+		d, err := time.ParseDuration(scaleDeployment.Duration)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse duration")
+		}
+		duration = d
+	}
+
+	resource := fmt.Sprintf("deployment/%s", scaleDeployment.Name)
+	b, err := kubectl.GetJSON(ctx, c, scaleDeployment.Namespace, resource)
+	if err != nil {
+		return errors.Wrap(err, "failed to get deployment")
+	}
+	deployment := struct {
+		Spec struct {
+			Replicas int `json:"replicas"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(b, &deployment); err != nil {
+		return errors.Wrap(err, "failed to unmarshal deployment")
+	}
+
+	if err := kubectl.ScaleDeployment(ctx, c, scaleDeployment.Namespace, scaleDeployment.Name, 0); err != nil {
+		return errors.Wrap(err, "failed to scale down")
+	}
+
+	// the deployment is scaled back up even when the experiment was cancelled
+	select {
+	case <-time.After(duration):
+	case <-ctx.Done():
+	}
+
+	if err := kubectl.ScaleDeployment(context.Background(), c, scaleDeployment.Namespace, scaleDeployment.Name, deployment.Spec.Replicas); err != nil {
+		return errors.Wrap(err, "failed to scale up")
+	}
+	if err := kubectl.WaitForRollout(ctx, c, scaleDeployment.Namespace, resource, timeout); err != nil {
+		return errors.Wrap(err, "failed to wait for scale up")
+	}
+
+	return nil
+}
+
+// restartNodeGroup will drain and replace each node in the eks node group, one at a time
+func restartNodeGroup(ctx context.Context, c *types.ClusterConfig, restartNodeGroup *types.RestartNodeGroupAction, nodeTerminator NodeTerminator, out io.Writer) (string, error) {
+	if c.Provider != "aws" {
+		return "", errors.Errorf("node groups cannot be restarted on %s clusters", c.Provider)
+	}
+	if nodeTerminator == nil {
+		return "", errors.New("node groups cannot be restarted without a node terminator")
+	}
+
+	nodeGroup := restartNodeGroup.NodeGroup
+	if nodeGroup == "" {
+		nodeGroup = c.Name
+	}
+
+	selector := fmt.Sprintf("%s=%s", eksNodeGroupLabel, nodeGroup)
+	nodes, err := kubectl.GetNodes(ctx, c)
+	if err != nil {
+		return nodeGroup, errors.Wrap(err, "failed to get nodes")
+	}
+
+	nodeGroupNodes := []kubectl.Node{}
+	for _, node := range nodes.Items {
+		if matchesSelector(node.Metadata.Labels, selector) {
+			nodeGroupNodes = append(nodeGroupNodes, node)
+		}
+	}
+	if len(nodeGroupNodes) == 0 {
+		return nodeGroup, errors.Errorf("no nodes found in node group %s", nodeGroup)
+	}
+
+	for _, node := range nodeGroupNodes {
+		// provider id is in the form aws:///<zone>/<instance id>
+		instanceID := node.Spec.ProviderID[strings.LastIndex(node.Spec.ProviderID, "/")+1:]
+
+		fmt.Fprintf(out, "replacing node %s (%s)\n", node.Metadata.Name, instanceID)
+
+		if err := kubectl.DrainNode(ctx, c, node.Metadata.Name, nodeReplaceTimeout); err != nil {
+			return nodeGroup, errors.Wrapf(err, "failed to drain node %s", node.Metadata.Name)
+		}
+		if err := nodeTerminator.TerminateNode(ctx, c, instanceID); err != nil {
+			return nodeGroup, errors.Wrapf(err, "failed to terminate instance %s", instanceID)
+		}
+		if err := waitForNodeReplaced(ctx, c, selector, node.Metadata.Name, len(nodeGroupNodes)); err != nil {
+			return nodeGroup, errors.Wrapf(err, "failed to wait for node %s to be replaced", node.Metadata.Name)
+		}
+	}
+
+	return nodeGroup, nil
+}
+
+// waitForNodeReplaced will wait until the node is gone and the expected number of
+// nodes matching the selector are ready
+func waitForNodeReplaced(ctx context.Context, c *types.ClusterConfig, selector string, nodeName string, count int) error {
+	deadline := time.Now().Add(nodeReplaceTimeout)
+	for time.Now().Before(deadline) {
+		nodes, err := kubectl.GetNodes(ctx, c)
+		if err == nil {
+			isGone := true
+			readyCount := 0
+			for _, node := range nodes.Items {
+				if node.Metadata.Name == nodeName {
+					isGone = false
+				}
+				if node.IsReady() && matchesSelector(node.Metadata.Labels, selector) {
+					readyCount++
+				}
+			}
+
+			if isGone && readyCount >= count {
+				return nil
+			}
+		}
+
+		select {
+		case <-time.After(15 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return errors.New("timed out")
+}
+
+// matchesSelector supports equality based selectors in the form key=value,key2=value2
+func matchesSelector(labels map[string]string, selector string) bool {
+	if selector == "" {
+		return true
+	}
+
+	for _, requirement := range strings.Split(selector, ",") {
+		parts := strings.SplitN(strings.TrimSpace(requirement), "=", 2)
+		if len(parts) != 2 {
+			return false
+		}
+
+		value, ok := labels[parts[0]]
+		if !ok || value != parts[1] {
+			return false
+		}
+	}
+
+	return true
+}
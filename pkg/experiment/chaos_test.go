@@ -0,0 +1,52 @@
+package experiment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchesSelector(t *testing.T) {
+	labels := map[string]string{
+		"eks.amazonaws.com/nodegroup": "grid-abc",
+		"kubernetes.io/os":            "linux",
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		expected bool
+	}{
+		{
+			name:     "empty selector",
+			selector: "",
+			expected: true,
+		},
+		{
+			name:     "single match",
+			selector: "eks.amazonaws.com/nodegroup=grid-abc",
+			expected: true,
+		},
+		{
+			name:     "multiple match",
+			selector: "eks.amazonaws.com/nodegroup=grid-abc, kubernetes.io/os=linux",
+			expected: true,
+		},
+		{
+			name:     "value mismatch",
+			selector: "kubernetes.io/os=windows",
+			expected: false,
+		},
+		{
+			name:     "missing label",
+			selector: "node-role=worker",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, matchesSelector(labels, test.selector))
+		})
+	}
+}
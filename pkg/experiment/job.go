@@ -0,0 +1,307 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	DefaultNamespace = "kubectl-grid"
+)
+
+type job struct {
+	Name                  string
+	Namespace             string
+	Image                 string
+	Command               []string
+	Env                   map[string]string
+	ServiceAccountName    string
+	BackoffLimit          int
+	ActiveDeadlineSeconds int64
+	// ConfigMaps is a map of config map name to the path to mount it at
+	ConfigMaps map[string]string
+}
+
+type jobStatus struct {
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string    `json:"name"`
+			CreationTimestamp time.Time `json:"creationTimestamp"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// runJob will create the job in the cluster, replacing any job with the same name, and
+// stream the logs to out until the job completes. the logs of the last pod are returned
+func runJob(ctx context.Context, c *types.ClusterConfig, j job, out io.Writer) ([]byte, error) {
+	if err := kubectl.Apply(ctx, c, namespaceManifest(j.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to create namespace")
+	}
+
+	if err := kubectl.DeleteResource(ctx, c, j.Namespace, fmt.Sprintf("job/%s", j.Name)); err != nil {
+		return nil, errors.Wrap(err, "failed to delete previous job")
+	}
+
+	manifest, err := jobManifest(j)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build job manifest")
+	}
+	if err := kubectl.Apply(ctx, c, manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to create job")
+	}
+
+	var logs bytes.Buffer
+	w := io.MultiWriter(&logs, out)
+	if err := kubectl.Logs(ctx, c, j.Namespace, fmt.Sprintf("job/%s", j.Name), true, w); err != nil {
+		return logs.Bytes(), errors.Wrap(err, "failed to follow job logs")
+	}
+
+	timeout := 30 * time.Minute
+	if j.ActiveDeadlineSeconds > 0 {
+		timeout = time.Duration(j.ActiveDeadlineSeconds)*time.Second + time.Minute
+	}
+	jobErr := waitForJob(ctx, c, j.Namespace, j.Name, timeout)
+
+	// when the job was retried, the logs that were followed are from the first pod
+	if j.BackoffLimit > 0 {
+		lastPodLogs, err := getLastJobPodLogs(ctx, c, j.Namespace, j.Name)
+		if err != nil {
+			return logs.Bytes(), errors.Wrap(err, "failed to get logs from last pod")
+		}
+		return lastPodLogs, errors.Wrap(jobErr, "job did not succeed")
+	}
+
+	return logs.Bytes(), errors.Wrap(jobErr, "job did not succeed")
+}
+
+// waitForJob will return nil when the job completes, or an error when it fails
+func waitForJob(ctx context.Context, c *types.ClusterConfig, namespace string, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		b, err := kubectl.GetJSON(ctx, c, namespace, fmt.Sprintf("job/%s", name))
+		if err != nil {
+			return errors.Wrap(err, "failed to get job")
+		}
+
+		status := jobStatus{}
+		if err := json.Unmarshal(b, &status); err != nil {
+			return errors.Wrap(err, "failed to unmarshal job")
+		}
+
+		for _, condition := range status.Status.Conditions {
+			if condition.Status != "True" {
+				continue
+			}
+			if condition.Type == "Complete" {
+				return nil
+			}
+			if condition.Type == "Failed" {
+				return errors.Errorf("job %s failed: %s", name, condition.Message)
+			}
+		}
+
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return errors.New("timed out")
+}
+
+func getLastJobPodLogs(ctx context.Context, c *types.ClusterConfig, namespace string, jobName string) ([]byte, error) {
+	b, err := kubectl.ListJSON(ctx, c, namespace, "pods", fmt.Sprintf("job-name=%s", jobName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	pods := podList{}
+	if err := json.Unmarshal(b, &pods); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pods")
+	}
+	if len(pods.Items) == 0 {
+		return nil, errors.New("no pods found for job")
+	}
+
+	lastPod := pods.Items[0]
+	for _, pod := range pods.Items {
+		if pod.Metadata.CreationTimestamp.After(lastPod.Metadata.CreationTimestamp) {
+			lastPod = pod
+		}
+	}
+
+	var logs bytes.Buffer
+	if err := kubectl.Logs(ctx, c, namespace, fmt.Sprintf("pod/%s", lastPod.Metadata.Name), false, &logs); err != nil {
+		return nil, errors.Wrap(err, "failed to get pod logs")
+	}
+
+	return logs.Bytes(), nil
+}
+
+func namespaceManifest(namespace string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+`, namespace)
+}
+
+func configMapManifest(name string, namespace string, data map[string]string) (string, error) {
+	configMap := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"data": data,
+	}
+
+	b, err := yaml.Marshal(configMap)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal config map")
+	}
+
+	return string(b), nil
+}
+
+func jobManifest(j job) (string, error) {
+	env := []map[string]interface{}{}
+	envNames := []string{}
+	for name := range j.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		env = append(env, map[string]interface{}{
+			"name":  name,
+			"value": j.Env[name],
+		})
+	}
+
+	volumes := []map[string]interface{}{}
+	volumeMounts := []map[string]interface{}{}
+	configMapNames := []string{}
+	for name := range j.ConfigMaps {
+		configMapNames = append(configMapNames, name)
+	}
+	sort.Strings(configMapNames)
+	for _, name := range configMapNames {
+		volumes = append(volumes, map[string]interface{}{
+			"name": name,
+			"configMap": map[string]interface{}{
+				"name": name,
+			},
+		})
+		volumeMounts = append(volumeMounts, map[string]interface{}{
+			"name":      name,
+			"mountPath": j.ConfigMaps[name],
+		})
+	}
+
+	podSpec := map[string]interface{}{
+		"restartPolicy": "Never",
+		"containers": []map[string]interface{}{
+			{
+				"name":         "grid",
+				"image":        j.Image,
+				"command":      j.Command,
+				"env":          env,
+				"volumeMounts": volumeMounts,
+			},
+		},
+		"volumes": volumes,
+	}
+	if j.ServiceAccountName != "" {
+		podSpec["serviceAccountName"] = j.ServiceAccountName
+	}
+
+	jobSpec := map[string]interface{}{
+		"backoffLimit": j.BackoffLimit,
+		"template": map[string]interface{}{
+			"spec": podSpec,
+		},
+	}
+	if j.ActiveDeadlineSeconds > 0 {
+		jobSpec["activeDeadlineSeconds"] = j.ActiveDeadlineSeconds
+	}
+
+	manifest := map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":      j.Name,
+			"namespace": j.Namespace,
+		},
+		"spec": jobSpec,
+	}
+
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal job")
+	}
+
+	return string(b), nil
+}
+
+// prefixWriter writes each line with the cluster name as a prefix, so that output
+// from many clusters can be told apart
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func newPrefixWriter(mu *sync.Mutex, w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{
+		mu:     mu,
+		w:      w,
+		prefix: prefix,
+	}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			// put the partial line back until the rest of it is written
+			p.buf.Reset()
+			p.buf.Write(line)
+			break
+		}
+
+		p.mu.Lock()
+		_, werr := fmt.Fprintf(p.w, "[%s] %s", p.prefix, line)
+		p.mu.Unlock()
+		if werr != nil {
+			return 0, werr
+		}
+	}
+
+	return len(b), nil
+}
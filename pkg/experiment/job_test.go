@@ -0,0 +1,76 @@
+package experiment
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newPrefixWriter(&sync.Mutex{}, &out, "cluster-a")
+
+	_, err := w.Write([]byte("first line\nsecond "))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("line\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "[cluster-a] first line\n[cluster-a] second line\n", out.String())
+}
+
+func Test_jobManifest(t *testing.T) {
+	manifest, err := jobManifest(job{
+		Name:      "grid-test",
+		Namespace: "kubectl-grid",
+		Image:     "busybox",
+		Command:   []string{"sh", "-c", "echo hi"},
+		Env: map[string]string{
+			"B": "2",
+			"A": "1",
+		},
+		ConfigMaps: map[string]string{
+			"grid-test": "/scripts",
+		},
+	})
+	require.NoError(t, err)
+
+	expected := `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: grid-test
+  namespace: kubectl-grid
+spec:
+  backoffLimit: 0
+  template:
+    spec:
+      containers:
+      - command:
+        - sh
+        - -c
+        - echo hi
+        env:
+        - name: A
+          value: "1"
+        - name: B
+          value: "2"
+        image: busybox
+        name: grid
+        volumeMounts:
+        - mountPath: /scripts
+          name: grid-test
+      restartPolicy: Never
+      volumes:
+      - configMap:
+          name: grid-test
+        name: grid-test
+`
+	assert.Equal(t, expected, manifest)
+}
+
+func Test_k6SummaryFromLogs(t *testing.T) {
+	assert.Nil(t, k6SummaryFromLogs([]byte("running\n")))
+	assert.Equal(t, []byte(`{"metrics":{}}`), k6SummaryFromLogs([]byte("running\n"+k6SummaryMarker+"\n{\"metrics\":{}}\n")))
+}
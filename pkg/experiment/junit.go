@@ -0,0 +1,126 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr,omitempty"`
+	Time      string          `xml:"time,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr,omitempty"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// MergeJUnitResults will combine the junit results from every cluster into a single
+// report with the suites named by cluster, and return the path to the report. Clusters
+// without results are reported as a suite with a single errored test
+func MergeJUnitResults(outputDir string, gridName string, results []*ClusterResult) (string, error) {
+	merged := junitTestSuites{}
+
+	for _, result := range results {
+		var junit []byte
+		if result.ResultsPath != "" {
+			b, err := ioutil.ReadFile(result.ResultsPath)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to read results for cluster %s", result.ClusterName)
+			}
+			junit = b
+		}
+
+		suites, err := clusterJUnitSuites(result.ClusterName, junit, result.Error)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse results for cluster %s", result.ClusterName)
+		}
+		merged.Suites = append(merged.Suites, suites...)
+	}
+
+	for _, suite := range merged.Suites {
+		merged.Tests += suite.Tests
+		merged.Failures += suite.Failures
+		merged.Errors += suite.Errors
+	}
+
+	b, err := xml.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal junit report")
+	}
+
+	return writeResultsFile(outputDir, gridName, "junit.xml", append([]byte(xml.Header), b...))
+}
+
+// clusterJUnitSuites returns the suites from the junit xml renamed for the cluster
+func clusterJUnitSuites(clusterName string, junit []byte, clusterError string) ([]junitTestSuite, error) {
+	if len(junit) == 0 {
+		return []junitTestSuite{
+			{
+				Name:   clusterName,
+				Tests:  1,
+				Errors: 1,
+				TestCases: []junitTestCase{
+					{
+						Name:  "job",
+						Error: &junitMessage{Message: clusterError},
+					},
+				},
+			},
+		}, nil
+	}
+
+	suites := []junitTestSuite{}
+	if bytes.Contains(junit, []byte("<testsuites")) {
+		parsed := junitTestSuites{}
+		if err := xml.Unmarshal(junit, &parsed); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal testsuites")
+		}
+		suites = parsed.Suites
+	} else {
+		parsed := junitTestSuite{}
+		if err := xml.Unmarshal(junit, &parsed); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal testsuite")
+		}
+		suites = append(suites, parsed)
+	}
+
+	for i := range suites {
+		if len(suites) == 1 || suites[i].Name == "" {
+			suites[i].Name = clusterName
+		} else {
+			suites[i].Name = fmt.Sprintf("%s/%s", clusterName, suites[i].Name)
+		}
+	}
+
+	return suites, nil
+}
@@ -0,0 +1,101 @@
+package experiment
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_clusterJUnitSuites(t *testing.T) {
+	tests := []struct {
+		name          string
+		junit         string
+		clusterError  string
+		expectedNames []string
+		expectedTests int
+		expectError   bool
+	}{
+		{
+			name:          "single testsuite",
+			junit:         `<testsuite name="e2e" tests="2" failures="1"><testcase name="a"/><testcase name="b"><failure message="boom"/></testcase></testsuite>`,
+			expectedNames: []string{"cluster-a"},
+			expectedTests: 2,
+		},
+		{
+			name:          "multiple testsuites",
+			junit:         `<?xml version="1.0"?><testsuites><testsuite name="api" tests="1"><testcase name="a"/></testsuite><testsuite name="ui" tests="1"><testcase name="b"/></testsuite></testsuites>`,
+			expectedNames: []string{"cluster-a/api", "cluster-a/ui"},
+			expectedTests: 2,
+		},
+		{
+			name:          "no results",
+			clusterError:  "job did not succeed",
+			expectedNames: []string{"cluster-a"},
+			expectedTests: 1,
+		},
+		{
+			name:        "invalid xml",
+			junit:       `<testsuite`,
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			suites, err := clusterJUnitSuites("cluster-a", []byte(test.junit), test.clusterError)
+			if test.expectError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			names := []string{}
+			tests := 0
+			for _, suite := range suites {
+				names = append(names, suite.Name)
+				tests += suite.Tests
+			}
+			assert.Equal(t, test.expectedNames, names)
+			assert.Equal(t, test.expectedTests, tests)
+		})
+	}
+}
+
+func Test_MergeJUnitResults(t *testing.T) {
+	req := require.New(t)
+
+	outputDir, err := ioutil.TempDir("", "junit")
+	req.NoError(err)
+	defer os.RemoveAll(outputDir)
+
+	clusterAPath := filepath.Join(outputDir, "cluster-a-junit.xml")
+	err = ioutil.WriteFile(clusterAPath, []byte(`<testsuite name="e2e" tests="2" failures="1"><testcase name="a"/><testcase name="b"><failure message="boom"/></testcase></testsuite>`), 0644)
+	req.NoError(err)
+
+	reportPath, err := MergeJUnitResults(outputDir, "grid", []*ClusterResult{
+		{ClusterName: "cluster-a", ResultsPath: clusterAPath},
+		{ClusterName: "cluster-b", Error: "timed out"},
+	})
+	req.NoError(err)
+	assert.Equal(t, filepath.Join(outputDir, "grid", "junit.xml"), reportPath)
+
+	b, err := ioutil.ReadFile(reportPath)
+	req.NoError(err)
+
+	report := junitTestSuites{}
+	req.NoError(xml.Unmarshal(b, &report))
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	req.Len(report.Suites, 2)
+	assert.Equal(t, "cluster-a", report.Suites[0].Name)
+	assert.Equal(t, "cluster-b", report.Suites[1].Name)
+	assert.Equal(t, "timed out", report.Suites[1].TestCases[0].Error.Message)
+}
@@ -0,0 +1,158 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+)
+
+// the k6 job prints the summary after this marker so it can be read from the logs
+const k6SummaryMarker = "---kubectl-grid-k6-summary---"
+
+func runK6Experiment(ctx context.Context, gridName string, c *types.ClusterConfig, e *types.Experiment, outputDir string, out io.Writer) (string, []byte, error) {
+	k6Spec := e.Spec.K6
+
+	script, err := ioutil.ReadFile(k6Spec.Script)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read script")
+	}
+
+	target, err := app.RenderClusterTemplate(c, k6Spec.Target)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to render target")
+	}
+
+	version := k6Spec.Version
+	if version == "" {
+		version = app.DefaultK6Version
+	}
+
+	namespace := k6Spec.Namespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	name := fmt.Sprintf("grid-k6-%s", e.Name)
+
+	configMap, err := configMapManifest(name, namespace, map[string]string{
+		"script.js": string(script),
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build script config map")
+	}
+	if err := kubectl.Apply(ctx, c, namespaceManifest(namespace)); err != nil {
+		return "", nil, errors.Wrap(err, "failed to create namespace")
+	}
+	if err := kubectl.Apply(ctx, c, configMap); err != nil {
+		return "", nil, errors.Wrap(err, "failed to create script config map")
+	}
+
+	// vus and duration are passed in k6's own env vars so that nothing from the spec is part of the shell script
+	env := map[string]string{
+		"TARGET_URL":   target,
+		"CLUSTER_NAME": c.Name,
+	}
+	if k6Spec.VUs > 0 {
+		env["K6_VUS"] = strconv.Itoa(k6Spec.VUs)
+	}
+	if k6Spec.Duration != "" {
+		env["K6_DURATION"] = k6Spec.Duration
+	}
+
+	j := job{
+		Name:      name,
+		Namespace: namespace,
+		Image:     fmt.Sprintf("loadimpact/k6:%s", version),
+		Command: []string{
+			"sh", "-c",
+			fmt.Sprintf("k6 run --summary-export /tmp/summary.json /scripts/script.js; rc=$?; echo %s; cat /tmp/summary.json; exit $rc", k6SummaryMarker),
+		},
+		Env: env,
+		ConfigMaps: map[string]string{
+			name: "/scripts",
+		},
+	}
+
+	logs, jobErr := runJob(ctx, c, j, out)
+
+	// the summary is written even when thresholds fail, so save it before checking the job error
+	summary := k6SummaryFromLogs(logs)
+	if summary == nil {
+		if jobErr != nil {
+			return "", logs, errors.Wrap(jobErr, "failed to run k6")
+		}
+		return "", logs, errors.New("k6 summary not found in job logs")
+	}
+
+	resultsPath, err := writeResultsFile(outputDir, gridName, fmt.Sprintf("%s-k6-summary.json", c.Name), summary)
+	if err != nil {
+		return "", logs, errors.Wrap(err, "failed to write k6 summary")
+	}
+
+	if jobErr != nil {
+		return resultsPath, logs, errors.Wrap(jobErr, "failed to run k6")
+	}
+
+	return resultsPath, logs, nil
+}
+
+// k6SummaryFromLogs returns the summary json printed after the marker, or nil
+func k6SummaryFromLogs(logs []byte) []byte {
+	parts := bytes.SplitN(logs, []byte(k6SummaryMarker), 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	summary := bytes.TrimSpace(parts[1])
+	if len(summary) == 0 {
+		return nil
+	}
+
+	return summary
+}
+
+// K6Summary is the summary exported by k6 run --summary-export
+type K6Summary struct {
+	Metrics map[string]map[string]interface{} `json:"metrics"`
+}
+
+// ReadK6Summary will read a summary written by a k6 experiment
+func ReadK6Summary(path string) (*K6Summary, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read summary")
+	}
+
+	summary := K6Summary{}
+	if err := json.Unmarshal(b, &summary); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal summary")
+	}
+
+	return &summary, nil
+}
+
+// Value returns the stat for the metric, for example ("http_req_duration", "p(95)")
+func (s K6Summary) Value(metric string, stat string) (float64, bool) {
+	stats, ok := s.Metrics[metric]
+	if !ok {
+		return 0, false
+	}
+
+	value, ok := stats[stat].(float64)
+	return value, ok
+}
+
+// IsK6Summary returns true if the path is a summary written by a k6 experiment
+func IsK6Summary(path string) bool {
+	return strings.HasSuffix(path, "-k6-summary.json")
+}
@@ -0,0 +1,150 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type RunOptions struct {
+	// OutputDir is where results are written, in a subdirectory named by grid
+	OutputDir string
+	// Out is where progress from every cluster is streamed, defaults to stdout
+	Out io.Writer
+	// NodeTerminator replaces nodes for restartNodeGroup chaos actions
+	NodeTerminator NodeTerminator
+}
+
+type ClusterResult struct {
+	ClusterName string    `json:"clusterName"`
+	ResultsPath string    `json:"resultsPath,omitempty"`
+	LogsPath    string    `json:"logsPath,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+}
+
+// Run will run the experiment on every cluster in the grid in parallel, and
+// return the results for each cluster
+func Run(ctx context.Context, g *types.GridConfig, e *types.Experiment, opts RunOptions) ([]*ClusterResult, error) {
+	if err := validateExperimentName(e.Name); err != nil {
+		return nil, errors.Wrap(err, "invalid experiment name")
+	}
+
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	outMu := sync.Mutex{}
+	clusters := g.ReadyClusters()
+	results := make([]*ClusterResult, len(clusters))
+
+	tasks := []orchestrator.Task{}
+	for i, c := range clusters {
+		result := &ClusterResult{
+			ClusterName: c.Name,
+		}
+		results[i] = result
+
+		c := c
+		tasks = append(tasks, orchestrator.Task{
+			Cluster: c.Name,
+			Run: func(ctx context.Context) error {
+				// when the progress is watched, the output is shown as the last message of the cluster
+				var out io.Writer = newPrefixWriter(&outMu, opts.Out, c.Name)
+				if watch.FromContext(ctx) != nil {
+					out = watch.Writer(ctx)
+				}
+
+				watch.Step(ctx, "experiment")
+				result.StartedAt = time.Now()
+				resultsPath, logs, err := runExperiment(ctx, g.Name, c, e, opts, out)
+				result.ResultsPath = resultsPath
+				result.FinishedAt = time.Now()
+				if err != nil {
+					result.Error = err.Error()
+				}
+				if len(logs) > 0 {
+					logsPath, writeErr := writeResultsFile(opts.OutputDir, g.Name, fmt.Sprintf("%s-logs.txt", c.Name), logs)
+					if writeErr != nil {
+						fmt.Fprintf(out, "failed to write logs: %s\n", writeErr.Error())
+					}
+					result.LogsPath = logsPath
+				}
+
+				return err
+			},
+		})
+	}
+
+	taskResults, err := orchestrator.Run(ctx, tasks, orchestrator.Options{})
+
+	// clusters that the experiment did not start on because it was cancelled have no error yet
+	for i, taskResult := range taskResults {
+		if taskResult.Err != nil && results[i].Error == "" {
+			results[i].Error = taskResult.Err.Error()
+		}
+	}
+
+	if err != nil {
+		return results, errors.Wrap(err, "experiment failed on one or more clusters")
+	}
+
+	return results, nil
+}
+
+// validateExperimentName returns an error if the jobs and config maps named after the
+// experiment would not be valid dns-1123 labels. grid-job- is the longest prefix used
+func validateExperimentName(name string) error {
+	if name == "" {
+		return errors.New("experiment metadata.name is required")
+	}
+
+	if errs := validation.IsDNS1123Label(fmt.Sprintf("grid-job-%s", name)); len(errs) > 0 {
+		return errors.Errorf("%q: %s", name, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// runExperiment returns the path to the results file and the logs from the cluster
+func runExperiment(ctx context.Context, gridName string, c *types.ClusterConfig, e *types.Experiment, opts RunOptions, out io.Writer) (string, []byte, error) {
+	if e.Spec.K6 != nil {
+		return runK6Experiment(ctx, gridName, c, e, opts.OutputDir, out)
+	}
+	if e.Spec.Job != nil {
+		return runJobExperiment(ctx, gridName, c, e, opts.OutputDir, out)
+	}
+	if e.Spec.Chaos != nil {
+		return runChaosExperiment(ctx, gridName, c, e, opts.OutputDir, opts.NodeTerminator, out)
+	}
+
+	return "", nil, errors.New("unknown experiment type")
+}
+
+// writeResultsFile writes the file to the grid's results dir and returns the path
+func writeResultsFile(outputDir string, gridName string, filename string, data []byte) (string, error) {
+	resultsDir := filepath.Join(outputDir, gridName)
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create results dir")
+	}
+
+	resultsPath := filepath.Join(resultsDir, filename)
+	if err := ioutil.WriteFile(resultsPath, data, 0644); err != nil {
+		return "", errors.Wrap(err, "failed to write results file")
+	}
+
+	return resultsPath, nil
+}
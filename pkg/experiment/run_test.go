@@ -0,0 +1,46 @@
+package experiment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateExperimentName(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectError bool
+	}{
+		{
+			name: "load-test",
+		},
+		{
+			name:        "",
+			expectError: true,
+		},
+		{
+			name:        "Load_Test",
+			expectError: true,
+		},
+		{
+			name:        "load; rm -rf /",
+			expectError: true,
+		},
+		{
+			name:        strings.Repeat("a", 60),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateExperimentName(test.name)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/app"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
+)
+
+// the test job prints the junit results after this marker so they can be read from the logs
+const junitMarker = "---kubectl-grid-junit---"
+
+// the env var the test job reads the junit results path from
+const junitPathEnv = "KUBECTL_GRID_JUNIT_PATH"
+
+type configMapData struct {
+	Data map[string]string `json:"data"`
+}
+
+func runJobExperiment(ctx context.Context, gridName string, c *types.ClusterConfig, e *types.Experiment, outputDir string, out io.Writer) (string, []byte, error) {
+	jobSpec := e.Spec.Job
+
+	namespace := jobSpec.Namespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	env := map[string]string{
+		"CLUSTER_NAME": c.Name,
+	}
+	for name, value := range jobSpec.Env {
+		rendered, err := app.RenderClusterTemplate(c, value)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "failed to render env %s", name)
+		}
+		env[name] = rendered
+	}
+
+	j := job{
+		Name:               fmt.Sprintf("grid-job-%s", e.Name),
+		Namespace:          namespace,
+		Image:              jobSpec.Image,
+		Command:            jobSpec.Command,
+		Env:                env,
+		ServiceAccountName: jobSpec.ServiceAccountName,
+		BackoffLimit:       jobSpec.Retries,
+	}
+
+	if jobSpec.Timeout != "" {
+		timeout, err := time.ParseDuration(jobSpec.Timeout)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to parse timeout")
+		}
+		j.ActiveDeadlineSeconds = int64(timeout.Seconds())
+	}
+
+	if jobSpec.JUnit != nil && jobSpec.JUnit.Path != "" {
+		if len(jobSpec.Command) == 0 {
+			return "", nil, errors.New("command is required to read junit results from a path")
+		}
+
+		// run the command as the positional args to the shell so that its args are not re-quoted,
+		// and pass the path in the env so that it's not interpreted by the shell
+		j.Env[junitPathEnv] = jobSpec.JUnit.Path
+		j.Command = append([]string{
+			"sh", "-c",
+			fmt.Sprintf(`"$@"; rc=$?; echo %s; cat "$%s"; exit $rc`, junitMarker, junitPathEnv),
+			"sh",
+		}, jobSpec.Command...)
+	}
+
+	if jobSpec.JUnit != nil && jobSpec.JUnit.ConfigMap != "" {
+		// results from a previous run should not be mistaken for this one
+		if err := kubectl.DeleteResource(ctx, c, namespace, fmt.Sprintf("configmap/%s", jobSpec.JUnit.ConfigMap)); err != nil {
+			return "", nil, errors.Wrap(err, "failed to delete previous results config map")
+		}
+	}
+
+	logs, jobErr := runJob(ctx, c, j, out)
+
+	if jobSpec.JUnit == nil {
+		if jobErr != nil {
+			return "", logs, errors.Wrap(jobErr, "failed to run job")
+		}
+		return "", logs, nil
+	}
+
+	junit, err := getJUnitResults(ctx, c, namespace, jobSpec.JUnit, logs)
+	if err != nil {
+		if jobErr != nil {
+			return "", logs, errors.Wrap(jobErr, "failed to run job")
+		}
+		return "", logs, errors.Wrap(err, "failed to get junit results")
+	}
+
+	resultsPath, err := writeResultsFile(outputDir, gridName, fmt.Sprintf("%s-junit.xml", c.Name), junit)
+	if err != nil {
+		return "", logs, errors.Wrap(err, "failed to write junit results")
+	}
+
+	if jobErr != nil {
+		return resultsPath, logs, errors.Wrap(jobErr, "failed to run job")
+	}
+
+	return resultsPath, logs, nil
+}
+
+func getJUnitResults(ctx context.Context, c *types.ClusterConfig, namespace string, junitSpec *types.JUnitResultsSpec, logs []byte) ([]byte, error) {
+	if junitSpec.ConfigMap != "" {
+		b, err := kubectl.GetJSON(ctx, c, namespace, fmt.Sprintf("configmap/%s", junitSpec.ConfigMap))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get results config map")
+		}
+
+		configMap := configMapData{}
+		if err := json.Unmarshal(b, &configMap); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal results config map")
+		}
+
+		key := junitSpec.ConfigMapKey
+		if key == "" {
+			key = "junit.xml"
+		}
+		junit, ok := configMap.Data[key]
+		if !ok {
+			return nil, errors.Errorf("key %s not found in results config map", key)
+		}
+
+		return []byte(junit), nil
+	}
+
+	parts := bytes.SplitN(logs, []byte(junitMarker), 2)
+	if len(parts) != 2 || len(bytes.TrimSpace(parts[1])) == 0 {
+		return nil, errors.New("junit results not found in job logs")
+	}
+
+	return bytes.TrimSpace(parts[1]), nil
+}
@@ -15,6 +15,10 @@ var (
 	l sync.Mutex
 )
 
+// configFileMode only allows the user to read the config file, because it has the kubeconfig
+// and admin console password for every cluster
+const configFileMode = 0600
+
 func lockConfig() {
 	l.Lock()
 }
@@ -37,7 +41,7 @@ func loadConfig(path string) (*types.GridsConfig, error) {
 			return nil, errors.Wrap(err, "failed to create config dir")
 		}
 
-		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		if err := ioutil.WriteFile(path, b, configFileMode); err != nil {
 			return nil, errors.Wrap(err, "failed to write config file")
 		}
 
@@ -63,10 +67,16 @@ func saveConfig(cfg *types.GridsConfig, path string) error {
 		return errors.Wrap(err, "failed to marshal config")
 	}
 
-	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+	if err := ioutil.WriteFile(path, b, configFileMode); err != nil {
 		return errors.Wrap(err, "failed to write config file")
 	}
 
+	// the mode is only set when the file is created, and config files written by older
+	// versions could be read by anyone
+	if err := os.Chmod(path, configFileMode); err != nil {
+		return errors.Wrap(err, "failed to set config file mode")
+	}
+
 	return nil
 }
 
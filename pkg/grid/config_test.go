@@ -68,3 +68,20 @@ func Test_loadConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_saveConfigMode(t *testing.T) {
+	req := require.New(t)
+
+	// a config file written by an older version that anyone could read
+	tmpFile, err := ioutil.TempFile("", "")
+	req.NoError(err)
+	defer os.RemoveAll(tmpFile.Name())
+	req.NoError(os.Chmod(tmpFile.Name(), 0644))
+
+	err = saveConfig(&types.GridsConfig{}, tmpFile.Name())
+	req.NoError(err)
+
+	info, err := os.Stat(tmpFile.Name())
+	req.NoError(err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -3,7 +3,6 @@ package grid
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 	"time"
 
@@ -13,70 +12,154 @@ import (
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 	"github.com/replicatedhq/kubectl-grid/pkg/kubectl"
 	"github.com/replicatedhq/kubectl-grid/pkg/logger"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 )
 
+type CreateOptions struct {
+	// Parallelism is the maximum number of clusters to create at once, or 0 for no limit
+	Parallelism int
+	// RollbackOnInterrupt will delete the clusters in the grid when ctx is cancelled
+	RollbackOnInterrupt bool
+	// Resume will create the clusters in a grid that was not completely created
+	Resume bool
+}
+
 // Create will create the grid defined in the gridSpec
 // the name of the grid will be the name in the metadata.name field
-// This function is synchronous and will not return until all clusters are ready
-func Create(configFilePath string, g *types.Grid) error {
-	completed := map[int]bool{}
-	completedChans := make([]chan string, len(g.Spec.Clusters))
-	for i := range g.Spec.Clusters {
-		completedChans[i] = make(chan string)
-		completed[i] = false
-	}
-
-	if err := addGridToConfig(configFilePath, g.Name); err != nil {
-		return errors.Wrap(err, "failed to add grid to config file")
-	}
-
-	// start listening for completed events
-	finished := make(chan bool)
-	go func() {
-		cases := make([]reflect.SelectCase, len(completedChans))
-		for i, ch := range completedChans {
-			cases[i] = reflect.SelectCase{
-				Dir:  reflect.SelectRecv,
-				Chan: reflect.ValueOf(ch),
-			}
+// This function is synchronous and will not return until all clusters are ready. When any
+// cluster fails, the error is an *orchestrator.Error with the error from each cluster
+func Create(ctx context.Context, configFilePath string, g *types.Grid, opts CreateOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "create", tracing.GridKey.String(g.Name))
+	defer func() { tracing.End(span, err) }()
+
+	completed := map[string]bool{}
+	if opts.Resume {
+		c, err := resumeGridInConfig(configFilePath, g.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to resume grid")
+		}
+		completed = c
+	} else {
+		if err := addGridToConfig(configFilePath, g.Name); err != nil {
+			return errors.Wrap(err, "failed to add grid to config file")
 		}
+	}
 
-		for {
-			i, completedErr, ok := reflect.Select(cases)
-			if ok {
-				if completedErr.String() != "" {
-					fmt.Printf("cluster %#v failed with error: %s\n", g.Spec.Clusters[i], completedErr.String())
-				}
+	ctx, log := logger.NewOperationLogger(ctx, g.Spec.Logger, logger.Fields{
+		logger.FieldOperation: "Create",
+		logger.FieldGrid:      g.Name,
+	})
 
-				completed[i] = true
-			}
+	tasks := []orchestrator.Task{}
+	for _, cluster := range g.Spec.Clusters {
+		clusterName := clusterSpecName(cluster)
+		if completed[clusterName] {
+			continue
+		}
+
+		// every cluster is recorded as pending, so that the grid shows the clusters that are waiting to be created
+		if err := addClusterToConfig(configFilePath, g.Name, pendingClusterConfig(cluster)); err != nil {
+			log.Finish()
+			return errors.Wrap(err, "failed to add cluster to config")
+		}
 
-			allCompleted := true
-			for _, v := range completed {
-				if !v {
-					allCompleted = false
+		cluster := cluster
+		tasks = append(tasks, orchestrator.Task{
+			Cluster: clusterName,
+			Run: func(ctx context.Context) error {
+				log := log.WithCluster(clusterName)
+				err := createCluster(ctx, g.Name, cluster, configFilePath, log)
+				if err != nil {
+					if phaseErr := setClusterPhase(configFilePath, g.Name, clusterName, types.ClusterPhaseFailed, err.Error()); phaseErr != nil {
+						log.Error(errors.Wrap(phaseErr, "failed to set cluster phase"))
+					}
 				}
-			}
+				return err
+			},
+		})
+	}
 
-			if allCompleted {
-				finished <- true
-				return
-			}
+	_, err = orchestrator.Run(ctx, tasks, orchestrator.Options{
+		Parallelism: opts.Parallelism,
+	})
+	log.Finish()
+
+	if ctx.Err() != nil {
+		if err := setGridPhase(configFilePath, g.Name, types.GridPhaseInterrupted); err != nil {
+			return errors.Wrap(err, "failed to set grid phase")
+		}
+
+		if !opts.RollbackOnInterrupt {
+			return errors.Wrap(ctx.Err(), "create was interrupted, run create with --resume to continue or delete the grid to clean up")
 		}
-	}()
 
-	// start each
-	for i, cluster := range g.Spec.Clusters {
-		log := logger.NewLogger(g.Spec.Logger)
-		go createCluster(g.Name, cluster, completedChans[i], configFilePath, log)
+		// the context was cancelled, so the rollback can't use it, but it still shows in the watch table
+		rollbackCtx := context.Background()
+		if t := watch.FromContext(ctx); t != nil {
+			rollbackCtx = watch.NewContext(rollbackCtx, t)
+		}
+		watch.Printf(ctx, "Create was interrupted, deleting the clusters in grid %s\n", g.Name)
+		if err := Delete(rollbackCtx, configFilePath, g, DeleteOptions{Parallelism: opts.Parallelism}); err != nil {
+			return errors.Wrap(err, "create was interrupted and failed to delete the clusters")
+		}
+
+		return errors.New("create was interrupted and the clusters were deleted")
+	}
+
+	if err != nil {
+		if err := setGridPhase(configFilePath, g.Name, types.GridPhaseFailed); err != nil {
+			return errors.Wrap(err, "failed to set grid phase")
+		}
+		return err
 	}
 
-	// wait for all channels to be closed
-	<-finished
+	if err := setGridPhase(configFilePath, g.Name, types.GridPhaseReady); err != nil {
+		return errors.Wrap(err, "failed to set grid phase")
+	}
 
 	return nil
 }
 
+// clusterSpecName is the name the cluster will have in the grid config
+func clusterSpecName(cluster *types.ClusterSpec) string {
+	if cluster.EKS != nil && cluster.EKS.ExistingCluster != nil {
+		return cluster.EKS.ExistingCluster.ClusterName
+	}
+	if cluster.EKS != nil && cluster.EKS.NewCluster != nil {
+		return cluster.EKS.NewCluster.GetDeterministicClusterName()
+	}
+
+	return "unknown"
+}
+
+// pendingClusterConfig is the cluster config before the cluster is created
+func pendingClusterConfig(cluster *types.ClusterSpec) *types.ClusterConfig {
+	now := time.Now()
+	clusterConfig := &types.ClusterConfig{
+		Name: clusterSpecName(cluster),
+		Status: &types.ClusterStatus{
+			Phase: types.ClusterPhasePending,
+		},
+		CreatedAt: &now,
+	}
+
+	if cluster.EKS != nil && cluster.EKS.ExistingCluster != nil {
+		clusterConfig.Provider = "aws"
+		clusterConfig.IsExisting = true
+		clusterConfig.Region = cluster.EKS.ExistingCluster.Region
+	}
+	if cluster.EKS != nil && cluster.EKS.NewCluster != nil {
+		clusterConfig.Provider = "aws"
+		clusterConfig.Region = cluster.EKS.NewCluster.Region
+		clusterConfig.Description = cluster.EKS.NewCluster.Description
+		clusterConfig.Version = cluster.EKS.NewCluster.Version
+	}
+
+	return clusterConfig
+}
+
 func addGridToConfig(configFilePath string, name string) error {
 	lockConfig()
 	defer unlockConfig()
@@ -98,6 +181,7 @@ func addGridToConfig(configFilePath string, name string) error {
 
 	gridConfig := types.GridConfig{
 		Name:           name,
+		Phase:          types.GridPhaseCreating,
 		ClusterConfigs: []*types.ClusterConfig{},
 	}
 	c.GridConfigs = append(c.GridConfigs, &gridConfig)
@@ -109,177 +193,239 @@ func addGridToConfig(configFilePath string, name string) error {
 	return nil
 }
 
+// startStep shows the step in the watch table, and returns a logger that adds the step to
+// every message
+func startStep(ctx context.Context, log logger.Logger, step string) logger.Logger {
+	watch.Step(ctx, step)
+	return log.WithFields(logger.Fields{logger.FieldStep: step})
+}
+
 // createCluster will create the cluster synchronously
-// when it's completed, it will return the error or "" as a string on the channel
-func createCluster(gridName string, cluster *types.ClusterSpec, completedCh chan string, configFilePath string, log logger.Logger) {
+func createCluster(ctx context.Context, gridName string, cluster *types.ClusterSpec, configFilePath string, log logger.Logger) error {
 	if cluster.EKS != nil {
-		createEKSCluster(gridName, cluster.EKS, completedCh, configFilePath, log)
-		return
+		return createEKSCluster(ctx, gridName, cluster.EKS, configFilePath, log)
 	}
 
-	completedCh <- "unknown cluster"
+	return errors.New("unknown cluster")
 }
 
-func createEKSCluster(gridName string, eksCluster *types.EKSSpec, completedCh chan string, configFilePath string, log logger.Logger) {
+func createEKSCluster(ctx context.Context, gridName string, eksCluster *types.EKSSpec, configFilePath string, log logger.Logger) error {
 	if eksCluster.ExistingCluster != nil {
-		connectExistingEKSCluster(gridName, eksCluster.ExistingCluster, completedCh, configFilePath, log)
-		return
+		return connectExistingEKSCluster(ctx, gridName, eksCluster.ExistingCluster, configFilePath, log)
 	} else if eksCluster.NewCluster != nil {
-		createNewEKSCluter(gridName, eksCluster.NewCluster, completedCh, configFilePath, log)
-		return
+		return createNewEKSCluter(ctx, gridName, eksCluster.NewCluster, configFilePath, log)
 	}
 
-	completedCh <- "eks cluster must have new or existing"
+	return errors.New("eks cluster must have new or existing")
 }
 
-func connectExistingEKSCluster(gridName string, existingEKSCluster *types.EKSExistingClusterSpec, completedCh chan string, configFilePath string, log logger.Logger) {
+func connectExistingEKSCluster(ctx context.Context, gridName string, existingEKSCluster *types.EKSExistingClusterSpec, configFilePath string, log logger.Logger) error {
+	log = startStep(ctx, log, "connect")
+	log.Info("Connecting to existing EKS cluster %s", existingEKSCluster.ClusterName)
+
 	accessKeyID, err := existingEKSCluster.AccessKeyID.String()
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to read access key id: %s", err.Error())
+		return errors.Wrap(err, "failed to read access key id")
 	}
 	secretAccessKey, err := existingEKSCluster.SecretAccessKey.String()
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to read secret access key: %s", err.Error())
+		return errors.Wrap(err, "failed to read secret access key")
 	}
 
-	kubeConfig, err := GetEKSClusterKubeConfig(existingEKSCluster.Region, accessKeyID, secretAccessKey, existingEKSCluster.ClusterName)
+	kubeConfig, err := GetEKSClusterKubeConfig(ctx, existingEKSCluster.Region, accessKeyID, secretAccessKey, existingEKSCluster.ClusterName)
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to get kubeconfig from eks cluster: %s", err.Error())
+		return errors.Wrap(err, "failed to get kubeconfig from eks cluster")
 	}
 
-	lockConfig()
-	defer unlockConfig()
-	c, err := loadConfig(configFilePath)
+	version, eksConfig, err := DescribeEKSCluster(ctx, existingEKSCluster.Region, accessKeyID, secretAccessKey, existingEKSCluster.ClusterName)
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to load config: %s", err.Error())
-		return
+		return errors.Wrap(err, "failed to describe eks cluster")
 	}
 
+	now := time.Now()
 	clusterConfig := types.ClusterConfig{
 		Name: existingEKSCluster.ClusterName,
 		// Description:
 		Provider:   "aws",
 		IsExisting: true,
 		Region:     existingEKSCluster.Region,
+		Version:    version,
 		Kubeconfig: kubeConfig,
+		Status: &types.ClusterStatus{
+			Phase: types.ClusterPhaseReady,
+		},
+		CreatedAt: &now,
+		EKS:       eksConfig,
 	}
 
-	for _, gridConfig := range c.GridConfigs {
-		if gridConfig.Name == gridName {
-			gridConfig.ClusterConfigs = append(gridConfig.ClusterConfigs, &clusterConfig)
-		}
-	}
-	if err := saveConfig(c, configFilePath); err != nil {
-		completedCh <- fmt.Sprintf("error saving config: %s", err.Error())
+	if err := addClusterToConfig(configFilePath, gridName, &clusterConfig); err != nil {
+		return errors.Wrap(err, "failed to add cluster to config")
 	}
 
-	completedCh <- ""
+	return nil
 }
 
 // createNewEKSCluster will create a complete, ready to use EKS cluster with all
 // security groups, vpcs, node pools, and everything else
-func createNewEKSCluter(gridName string, newEKSCluster *types.EKSNewClusterSpec, completedCh chan string, configFilePath string, log logger.Logger) {
+func createNewEKSCluter(ctx context.Context, gridName string, newEKSCluster *types.EKSNewClusterSpec, configFilePath string, log logger.Logger) error {
 	clusterName := newEKSCluster.GetDeterministicClusterName()
 
 	log.Info("Creating EKS cluster with all required dependencies with name %s", clusterName)
 
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(newEKSCluster.Region))
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(newEKSCluster.Region))
 	if err != nil {
-		completedCh <- fmt.Sprintf("error loading aws config: %s", err.Error())
-		return
+		return errors.Wrap(err, "failed to load aws config")
 	}
 
 	accessKeyID, err := newEKSCluster.AccessKeyID.String()
 	if err != nil {
-		completedCh <- fmt.Sprintf("error retreiving access key id: %s", err.Error())
-		return
+		return errors.Wrap(err, "failed to read access key id")
 	}
 	secretAccessKey, err := newEKSCluster.SecretAccessKey.String()
 	if err != nil {
-		completedCh <- fmt.Sprintf("error retrieving secret access key: %s", err.Error())
-		return
+		return errors.Wrap(err, "failed to read secret access key")
 	}
 
 	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := setClusterPhase(configFilePath, gridName, clusterName, types.ClusterPhaseProvisioning, ""); err != nil {
+		return errors.Wrap(err, "failed to set cluster phase")
+	}
+
+	partial := types.PartialClusterConfig{
+		Name:     clusterName,
+		Provider: "aws",
+		Region:   newEKSCluster.Region,
+	}
+
+	log = startStep(ctx, log, "vpc")
 	log.Info("Creating VPC for EKS cluster")
-	vpc, err := ensureEKSClusterVPC(cfg)
+	vpc, err := ensureEKSClusterVPC(ctx, cfg)
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to create EKS cluster vpc: %s", err.Error())
-		return
+		return errors.Wrap(err, "failed to create eks cluster vpc")
+	}
+	if err := setClusterCondition(configFilePath, gridName, clusterName, types.ClusterConditionVPCReady); err != nil {
+		return errors.Wrap(err, "failed to set cluster condition")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
+	log = startStep(ctx, log, "control plane")
 	log.Info("Creating EKS Cluster Control Plane")
-	cluster, err := ensureEKSCluterControlPlane(cfg, newEKSCluster, clusterName, vpc)
+	if err := recordPartialClusterResource(configFilePath, gridName, partial, types.ClusterResourceControlPlane); err != nil {
+		return errors.Wrap(err, "failed to record control plane")
+	}
+	cluster, err := ensureEKSCluterControlPlane(ctx, cfg, newEKSCluster, clusterName, vpc)
 	if err != nil {
 		if !strings.Contains(err.Error(), "Cluster already exists with name") {
-			completedCh <- fmt.Sprintf("failed to create eks cluster control plane: %s", err.Error())
-			return
+			return errors.Wrap(err, "failed to create eks cluster control plane")
 		}
 	}
 
 	log.Info("Waiting for EKS Cluster Control Plane to be ready (this can take a while, 15 minutes is not unusual)")
-	if err := waitForClusterToBeActive(newEKSCluster, accessKeyID, secretAccessKey, clusterName); err != nil {
-		completedCh <- fmt.Sprintf("cluster did not become ready")
-		return
+	if err := waitForClusterToBeActive(ctx, newEKSCluster, accessKeyID, secretAccessKey, clusterName); err != nil {
+		return errors.Wrap(err, "cluster did not become ready")
+	}
+	if err := setClusterCondition(configFilePath, gridName, clusterName, types.ClusterConditionControlPlaneActive); err != nil {
+		return errors.Wrap(err, "failed to set cluster condition")
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log = startStep(ctx, log, "node group")
 	log.Info("Creating EKS Cluster Node Group")
-	_, err = ensureEKSClusterNodeGroup(cfg, cluster, clusterName, vpc)
+	if err := recordPartialClusterResource(configFilePath, gridName, partial, types.ClusterResourceNodeGroup); err != nil {
+		return errors.Wrap(err, "failed to record node group")
+	}
+	_, err = ensureEKSClusterNodeGroup(ctx, cfg, cluster, clusterName, vpc)
 	if err != nil {
 		if !strings.Contains(err.Error(), "NodeGroup already exists") {
-			completedCh <- fmt.Sprintf("failed to create eks cluster node pool: %s", err.Error())
-			return
+			return errors.Wrap(err, "failed to create eks cluster node pool")
 		}
 	}
 
-	kubeConfig, err := GetEKSClusterKubeConfig(newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
+	log.Info("Waiting for EKS Cluster Node Group to be active")
+	if err := waitForNodeGroupToBeActive(ctx, newEKSCluster, accessKeyID, secretAccessKey, clusterName); err != nil {
+		return errors.Wrap(err, "node group did not become active")
+	}
+	if err := setClusterCondition(configFilePath, gridName, clusterName, types.ClusterConditionNodeGroupActive); err != nil {
+		return errors.Wrap(err, "failed to set cluster condition")
+	}
+
+	kubeConfig, err := GetEKSClusterKubeConfig(ctx, newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
 	if err != nil {
-		completedCh <- fmt.Sprintf("failed to get kubeconfig from eks cluster: %s", err.Error())
+		return errors.Wrap(err, "failed to get kubeconfig from eks cluster")
 	}
 
-	clusterConfig := types.ClusterConfig{
-		Name:        clusterName,
-		Description: newEKSCluster.Description,
-		Provider:    "aws",
-		IsExisting:  false,
-		Region:      newEKSCluster.Region,
-		Kubeconfig:  kubeConfig,
-	}
-
-	func() {
-		lockConfig()
-		defer unlockConfig()
-		c, err := loadConfig(configFilePath)
-		if err != nil {
-			completedCh <- fmt.Sprintf("failed to load config: %s", err.Error())
-			return
-		}
+	version, eksConfig, err := DescribeEKSCluster(ctx, newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
+	if err != nil {
+		return errors.Wrap(err, "failed to describe eks cluster")
+	}
 
-		for _, gridConfig := range c.GridConfigs {
-			if gridConfig.Name == gridName {
-				gridConfig.ClusterConfigs = append(gridConfig.ClusterConfigs, &clusterConfig)
-			}
-		}
-		if err := saveConfig(c, configFilePath); err != nil {
-			completedCh <- fmt.Sprintf("error saving config: %s", err.Error())
-		}
-	}()
+	clusterConfig := types.ClusterConfig{}
+	err = updateClusterConfig(configFilePath, gridName, clusterName, func(c *types.ClusterConfig) {
+		c.Kubeconfig = kubeConfig
+		c.Version = version
+		c.EKS = eksConfig
+		clusterConfig = *c
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save kubeconfig")
+	}
 
-	if err := ensureEKSAuthMap(&clusterConfig, vpc.RoleArn); err != nil {
-		completedCh <- fmt.Sprintf("failed to ensure aws-auth configmap: %s", err.Error())
+	if err := ensureEKSAuthMap(ctx, &clusterConfig, vpc.RoleArn); err != nil {
+		return errors.Wrap(err, "failed to ensure aws-auth configmap")
 	}
 
+	log = startStep(ctx, log, "nodes")
 	log.Info("Waiting for nodes to become ready")
-	if err := waitForNodes(&clusterConfig); err != nil {
-		completedCh <- fmt.Sprintf("failed to wait for nodes to join: %s", err.Error())
-		return
+	if err := waitForNodes(ctx, &clusterConfig); err != nil {
+		return errors.Wrap(err, "failed to wait for nodes to join")
+	}
+
+	err = updateClusterConfig(configFilePath, gridName, clusterName, func(c *types.ClusterConfig) {
+		c.SetCondition(types.ClusterConditionNodesReady, types.ConditionStatusTrue, "")
+		c.SetPhase(types.ClusterPhaseReady, "")
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to set cluster phase")
 	}
 
-	completedCh <- ""
+	if err := removePartialCluster(configFilePath, gridName, clusterName); err != nil {
+		return errors.Wrap(err, "failed to record cluster as created")
+	}
+
+	return nil
 }
 
-func ensureEKSAuthMap(c *types.ClusterConfig, roleArn string) error {
+// addClusterToConfig will add the cluster to the grid, replacing a cluster with the same
+// name when the create was resumed
+func addClusterToConfig(configFilePath string, gridName string, clusterConfig *types.ClusterConfig) error {
+	return updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		for i, c := range g.ClusterConfigs {
+			if c.Name == clusterConfig.Name {
+				g.ClusterConfigs[i] = clusterConfig
+				return nil
+			}
+		}
+
+		g.ClusterConfigs = append(g.ClusterConfigs, clusterConfig)
+		return nil
+	})
+}
+
+func ensureEKSAuthMap(ctx context.Context, c *types.ClusterConfig, roleArn string) (err error) {
+	_, span := tracing.Start(ctx, "ensure aws-auth", tracing.StepKey.String("node group"))
+	defer func() { tracing.End(span, err) }()
+
 	// ARN can't be a path, so if it's more than 2 parts, everything in the middle needs to be removed
 	arnParts := strings.Split(roleArn, "/")
 	if len(arnParts) > 2 {
@@ -302,17 +448,20 @@ data:
 
 `
 	yamlDoc = fmt.Sprintf(yamlDoc, roleArn)
-	if err := kubectl.Apply(c, yamlDoc); err != nil {
+	if err := kubectl.Apply(ctx, c, yamlDoc); err != nil {
 		return errors.Wrap(err, "failed to apply aws-auth configmap")
 	}
 
 	return nil
 }
 
-func waitForNodes(c *types.ClusterConfig) error {
+func waitForNodes(ctx context.Context, c *types.ClusterConfig) (err error) {
+	_, span := tracing.Start(ctx, "wait for nodes", tracing.StepKey.String("nodes"))
+	defer func() { tracing.End(span, err) }()
+
 	sleepTime := 10 * time.Second
 	for i := 0; i < 12; i++ {
-		nodes, err := kubectl.GetNodes(c)
+		nodes, err := kubectl.GetNodes(ctx, c)
 		if err != nil {
 			return errors.Wrap(err, "failed to get nodes")
 		}
@@ -329,7 +478,11 @@ func waitForNodes(c *types.ClusterConfig) error {
 			return nil
 		}
 
-		time.Sleep(sleepTime)
+		select {
+		case <-time.After(sleepTime):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return errors.New("timed out")
@@ -2,27 +2,45 @@ package grid
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 	"github.com/replicatedhq/kubectl-grid/pkg/logger"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
 )
 
-func Delete(configFilePath string, g *types.Grid) error {
+type DeleteOptions struct {
+	// Parallelism is the maximum number of clusters to delete at once, or 0 for no limit
+	Parallelism int
+}
+
+// Delete will delete the clusters that were created for the grid, and remove the grid from
+// the config file. When any cluster fails to delete, the grid is kept in the config file so
+// that the delete can be retried, and the error is an *orchestrator.Error
+func Delete(ctx context.Context, configFilePath string, g *types.Grid, opts DeleteOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "delete", tracing.GridKey.String(g.Name))
+	defer func() { tracing.End(span, err) }()
+
 	gridConfigs, err := List(configFilePath)
 	if err != nil {
 		return err
 	}
 
-	wg := sync.WaitGroup{}
+	ctx, log := logger.NewOperationLogger(ctx, g.Spec.Logger, logger.Fields{
+		logger.FieldOperation: "Delete",
+		logger.FieldGrid:      g.Name,
+	})
+
+	tasks := []orchestrator.Task{}
 	for _, gridConfig := range gridConfigs {
-		for _, clusterConfig := range gridConfig.ClusterConfigs {
+		for _, toDelete := range clustersToDelete(gridConfig) {
+			clusterConfig := toDelete.config
 			for _, cluster := range g.Spec.Clusters {
-				if cluster.EKS == nil && cluster.EKS.NewCluster == nil {
+				if cluster.EKS == nil || cluster.EKS.NewCluster == nil {
 					continue
 				}
 
@@ -30,22 +48,29 @@ func Delete(configFilePath string, g *types.Grid) error {
 					continue
 				}
 
-				wg.Add(1)
-				go func(config *types.ClusterConfig, cluster *types.ClusterSpec) {
-					defer wg.Done()
-
-					log := logger.NewLogger(g.Spec.Logger)
-
-					err := deleteCluster(config, cluster, log)
-					if err != nil {
-						fmt.Printf("cluster %s delete failed with error: %v\n", config.Name, err)
-					}
-				}(clusterConfig, cluster)
+				gridName, clusterConfig, resources, cluster := gridConfig.Name, clusterConfig, toDelete.resources, cluster
+				tasks = append(tasks, orchestrator.Task{
+					Cluster: clusterConfig.Name,
+					Run: func(ctx context.Context) error {
+						log := log.WithCluster(clusterConfig.Name)
+						err := deleteCluster(ctx, configFilePath, gridName, clusterConfig, resources, cluster, log)
+						if err != nil {
+							if phaseErr := setClusterPhase(configFilePath, gridName, clusterConfig.Name, types.ClusterPhaseFailed, err.Error()); phaseErr != nil {
+								log.Error(errors.Wrap(phaseErr, "failed to set cluster phase"))
+							}
+						}
+						return err
+					},
+				})
 			}
 		}
 	}
 
-	wg.Wait()
+	_, err = orchestrator.Run(ctx, tasks, orchestrator.Options{Parallelism: opts.Parallelism})
+	log.Finish()
+	if err != nil {
+		return err
+	}
 
 	if err := removeGridFromConfig(g.Name, configFilePath); err != nil {
 		return errors.Wrap(err, "failed to remove grid from config")
@@ -54,20 +79,83 @@ func Delete(configFilePath string, g *types.Grid) error {
 	return nil
 }
 
-func deleteCluster(c *types.ClusterConfig, cluster *types.ClusterSpec, log logger.Logger) error {
+// clusterToDelete is a cluster in the grid, with the resources that were created for it in
+// the order they were created
+type clusterToDelete struct {
+	config    *types.ClusterConfig
+	resources []string
+}
+
+// clustersToDelete returns the clusters in the grid, including clusters that were not
+// completely created. Only the resources that were recorded are deleted from a cluster
+// that was not completely created
+func clustersToDelete(gridConfig *types.GridConfig) []clusterToDelete {
+	partialClusters := map[string]*types.PartialClusterConfig{}
+	for _, p := range gridConfig.PartialClusters {
+		partialClusters[p.Name] = p
+	}
+
+	clusters := []clusterToDelete{}
+	found := map[string]bool{}
+	for _, c := range gridConfig.ClusterConfigs {
+		found[c.Name] = true
+
+		resources := []string{types.ClusterResourceControlPlane, types.ClusterResourceNodeGroup}
+		if p, ok := partialClusters[c.Name]; ok {
+			resources = p.Resources
+		} else if phase := c.GetPhase(); phase == types.ClusterPhasePending || phase == types.ClusterPhaseProvisioning {
+			// a resource is recorded before it's created, so nothing was created yet
+			resources = nil
+		}
+		clusters = append(clusters, clusterToDelete{config: c, resources: resources})
+	}
+
+	for _, p := range gridConfig.PartialClusters {
+		if found[p.Name] {
+			continue
+		}
+		clusters = append(clusters, clusterToDelete{
+			config: &types.ClusterConfig{
+				Name:     p.Name,
+				Provider: p.Provider,
+				Region:   p.Region,
+			},
+			resources: p.Resources,
+		})
+	}
+
+	return clusters
+}
+
+func deleteCluster(ctx context.Context, configFilePath string, gridName string, c *types.ClusterConfig, resources []string, cluster *types.ClusterSpec, log logger.Logger) error {
 	if c.Provider == "aws" {
-		return deleteNewEKSCluster(c, cluster.EKS, log)
+		return deleteNewEKSCluster(ctx, configFilePath, gridName, c, resources, cluster.EKS, log)
 	}
 
 	return nil
 }
 
-func deleteNewEKSCluster(c *types.ClusterConfig, cluster *types.EKSSpec, log logger.Logger) error {
-	clusterName := c.GetDeterministicClusterName()
+// deleteNewEKSCluster deletes the resources in the reverse of the order they were created. The
+// vpc is shared by every grid, so it's not deleted
+func deleteNewEKSCluster(ctx context.Context, configFilePath string, gridName string, c *types.ClusterConfig, resources []string, cluster *types.EKSSpec, log logger.Logger) error {
+	// the name of a new cluster is the deterministic name from the grid spec
+	clusterName := c.Name
 
 	log.Info("Deleting EKS cluster %s", clusterName)
 
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(c.Region))
+	// clusters that were only partially created may not be in the grid config, so the
+	// status is recorded when it can be
+	updateStatus := func(fn func(c *types.ClusterConfig)) {
+		if err := updateClusterConfig(configFilePath, gridName, clusterName, fn); err != nil {
+			log.Info("Not recording status of cluster %s: %s", clusterName, err.Error())
+		}
+	}
+
+	updateStatus(func(c *types.ClusterConfig) {
+		c.SetPhase(types.ClusterPhaseDeleting, "")
+	})
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.Region))
 	if err != nil {
 		return errors.Wrap(err, "failed to load aws config")
 	}
@@ -83,22 +171,67 @@ func deleteNewEKSCluster(c *types.ClusterConfig, cluster *types.EKSSpec, log log
 
 	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
-	log.Info("Deleting node group for EKS cluster (this may take a few minutes)")
-	err = deleteEKSNodeGroup(cfg, clusterName, clusterName)
-	if err != nil {
-		return errors.Wrap(err, "failed to delete node group")
-	}
+	for i := len(resources) - 1; i >= 0; i-- {
+		switch resources[i] {
+		case types.ClusterResourceNodeGroup:
+			log = startStep(ctx, log, "node group")
+			log.Info("Deleting node group for EKS cluster (this may take a few minutes)")
+			err = deleteEKSNodeGroup(ctx, cfg, clusterName, clusterName)
+			if err != nil {
+				return errors.Wrap(err, "failed to delete node group")
+			}
 
-	err = waitEKSNodeGroupGone(cfg, clusterName, clusterName)
-	if err != nil {
-		return errors.Wrap(err, "failed to wait for node group delete")
-	}
+			err = waitEKSNodeGroupGone(ctx, cfg, clusterName, clusterName)
+			if err != nil {
+				return errors.Wrap(err, "failed to wait for node group delete")
+			}
+			updateStatus(func(c *types.ClusterConfig) {
+				c.SetCondition(types.ClusterConditionNodeGroupActive, types.ConditionStatusFalse, "node group was deleted")
+				c.SetCondition(types.ClusterConditionNodesReady, types.ConditionStatusFalse, "node group was deleted")
+			})
+
+		case types.ClusterResourceControlPlane:
+			log = startStep(ctx, log, "control plane")
+			log.Info("Deleting EKS cluster")
+			err = waitForEKSClusterDeletable(ctx, c.Region, accessKeyID, secretAccessKey, clusterName)
+			if err != nil {
+				return errors.Wrap(err, "failed to wait for cluster to be deletable")
+			}
 
-	log.Info("Deleting EKS cluster")
-	err = deleteEKSCluster(cfg, clusterName)
-	if err != nil {
-		return errors.Wrap(err, "failed to delete cluster")
+			err = deleteEKSCluster(ctx, cfg, clusterName)
+			if err != nil {
+				return errors.Wrap(err, "failed to delete cluster")
+			}
+			updateStatus(func(c *types.ClusterConfig) {
+				c.SetCondition(types.ClusterConditionControlPlaneActive, types.ConditionStatusFalse, "control plane was deleted")
+			})
+		}
 	}
 
 	return nil
 }
+
+// waitForEKSClusterDeletable will wait for a control plane that is still being created,
+// because EKS can't delete a cluster until it's active
+func waitForEKSClusterDeletable(ctx context.Context, region string, accessKeyID string, secretAccessKey string, clusterName string) error {
+	for i := 0; i < 120; i++ {
+		isReady, err := getEKSClusterIsReady(ctx, region, accessKeyID, secretAccessKey, clusterName)
+		if err != nil {
+			if isEKSNotFound(err) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get cluster status")
+		}
+		if isReady {
+			return nil
+		}
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return errors.New("timed out")
+}
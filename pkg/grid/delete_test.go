@@ -0,0 +1,44 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_clustersToDelete(t *testing.T) {
+	gridConfig := &types.GridConfig{
+		Name: "grid",
+		ClusterConfigs: []*types.ClusterConfig{
+			{Name: "ready", Provider: "aws", Status: &types.ClusterStatus{Phase: types.ClusterPhaseReady}},
+			{Name: "created-before-status", Provider: "aws"},
+			{Name: "interrupted", Provider: "aws", Status: &types.ClusterStatus{Phase: types.ClusterPhaseProvisioning}},
+			{Name: "pending", Provider: "aws", Status: &types.ClusterStatus{Phase: types.ClusterPhasePending}},
+			{Name: "failed-delete", Provider: "aws", Status: &types.ClusterStatus{Phase: types.ClusterPhaseFailed}},
+		},
+		PartialClusters: []*types.PartialClusterConfig{
+			{Name: "interrupted", Provider: "aws", Region: "us-east-1", Resources: []string{types.ClusterResourceControlPlane}},
+			{Name: "not-in-grid", Provider: "aws", Region: "us-west-2", Resources: []string{types.ClusterResourceControlPlane, types.ClusterResourceNodeGroup}},
+		},
+	}
+
+	allResources := []string{types.ClusterResourceControlPlane, types.ClusterResourceNodeGroup}
+	want := map[string][]string{
+		"ready":                 allResources,
+		"created-before-status": allResources,
+		"interrupted":           {types.ClusterResourceControlPlane},
+		"pending":               nil,
+		"failed-delete":         allResources,
+		"not-in-grid":           allResources,
+	}
+
+	got := map[string][]string{}
+	for _, c := range clustersToDelete(gridConfig) {
+		got[c.config.Name] = c.resources
+		if c.config.Name == "not-in-grid" {
+			assert.Equal(t, "us-west-2", c.config.Region)
+		}
+	}
+	assert.Equal(t, want, got)
+}
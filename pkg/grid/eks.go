@@ -20,6 +20,8 @@ import (
 	smithy "github.com/aws/smithy-go"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func isEKSNotFound(err error) bool {
@@ -39,15 +41,15 @@ func isEKSNotFound(err error) bool {
 	return ok
 }
 
-func GetEKSClusterKubeConfig(region string, accessKeyID string, secretAccessKey string, clusterName string) (string, error) {
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+func GetEKSClusterKubeConfig(ctx context.Context, region string, accessKeyID string, secretAccessKey string, clusterName string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to load aws config")
 	}
 	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
 	svc := eks.NewFromConfig(cfg)
-	result, err := svc.DescribeCluster(context.Background(), &eks.DescribeClusterInput{
+	result, err := svc.DescribeCluster(ctx, &eks.DescribeClusterInput{
 		Name: aws.String(clusterName),
 	})
 	if err != nil {
@@ -89,15 +91,51 @@ users:
 	return b, nil
 }
 
-func GetEKSClusterNodePoolIsReady(region string, accessKeyID string, secretAccessKey string, clusterName string) (bool, error) {
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+// DescribeEKSCluster returns the kubernetes version and the AWS resources of the cluster
+func DescribeEKSCluster(ctx context.Context, region string, accessKeyID string, secretAccessKey string, clusterName string) (string, *types.EKSClusterConfig, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to load aws config")
+	}
+	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
+
+	svc := eks.NewFromConfig(cfg)
+	result, err := svc.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(clusterName),
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to describe cluster")
+	}
+
+	nodeGroups, err := svc.ListNodegroups(ctx, &eks.ListNodegroupsInput{
+		ClusterName: aws.String(clusterName),
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to list node groups")
+	}
+
+	eksConfig := types.EKSClusterConfig{
+		Endpoint:   stringValue(result.Cluster.Endpoint),
+		NodeGroups: nodeGroups.Nodegroups,
+	}
+	if result.Cluster.ResourcesVpcConfig != nil {
+		eksConfig.VPCID = stringValue(result.Cluster.ResourcesVpcConfig.VpcId)
+		eksConfig.SubnetIDs = result.Cluster.ResourcesVpcConfig.SubnetIds
+		eksConfig.SecurityGroupIDs = result.Cluster.ResourcesVpcConfig.SecurityGroupIds
+	}
+
+	return stringValue(result.Cluster.Version), &eksConfig, nil
+}
+
+func GetEKSClusterNodePoolIsReady(ctx context.Context, region string, accessKeyID string, secretAccessKey string, clusterName string) (bool, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return false, errors.Wrap(err, "failed to load aws config")
 	}
 	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
 	svc := eks.NewFromConfig(cfg)
-	result, err := svc.DescribeNodegroup(context.Background(), &eks.DescribeNodegroupInput{
+	result, err := svc.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
 		ClusterName:   aws.String(clusterName),
 		NodegroupName: aws.String(clusterName),
 	})
@@ -108,17 +146,86 @@ func GetEKSClusterNodePoolIsReady(region string, accessKeyID string, secretAcces
 	return result.Nodegroup.Status == ekstypes.NodegroupStatusActive, nil
 }
 
+// TerminateEKSInstance will terminate the ec2 instance backing a node, the node group
+// will replace it with a new instance
+func TerminateEKSInstance(ctx context.Context, region string, accessKeyID string, secretAccessKey string, instanceID string) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return errors.Wrap(err, "failed to load aws config")
+	}
+	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
+
+	svc := ec2.NewFromConfig(cfg)
+	_, err = svc.TerminateInstances(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to terminate instance")
+	}
+
+	return nil
+}
+
+// EKSNodeTerminator terminates the instances backing the nodes of eks clusters in a grid,
+// with the aws credentials that were written into the cluster's kubeconfig
+type EKSNodeTerminator struct{}
+
+func (EKSNodeTerminator) TerminateNode(ctx context.Context, c *types.ClusterConfig, instanceID string) error {
+	if c.Provider != "aws" {
+		return errors.Errorf("nodes cannot be terminated on %s clusters", c.Provider)
+	}
+
+	accessKeyID, secretAccessKey, err := eksCredentialsFromKubeconfig(c.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get credentials")
+	}
+
+	return TerminateEKSInstance(ctx, c.Region, accessKeyID, secretAccessKey, instanceID)
+}
+
+// eksCredentialsFromKubeconfig returns the access key id and secret access key from the env
+// of the exec credentials in a kubeconfig written by GetEKSClusterKubeConfig
+func eksCredentialsFromKubeconfig(kubeconfig string) (string, string, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to load kubeconfig")
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", "", errors.Errorf("context %q not found", config.CurrentContext)
+	}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok || authInfo.Exec == nil {
+		return "", "", errors.New("kubeconfig does not use exec credentials")
+	}
+
+	accessKeyID, secretAccessKey := "", ""
+	for _, env := range authInfo.Exec.Env {
+		switch env.Name {
+		case "AWS_ACCESS_KEY_ID":
+			accessKeyID = env.Value
+		case "AWS_SECRET_ACCESS_KEY":
+			secretAccessKey = env.Value
+		}
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return "", "", errors.New("kubeconfig does not have aws credentials")
+	}
+
+	return accessKeyID, secretAccessKey, nil
+}
+
 // getEKSClusterIsReady will return a bool if the cluster is completely ready for workloads
 // we look at the cluster status in the AWS response to be "active"
-func getEKSClusterIsReady(region string, accessKeyID string, secretAccessKey string, clusterName string) (bool, error) {
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+func getEKSClusterIsReady(ctx context.Context, region string, accessKeyID string, secretAccessKey string, clusterName string) (bool, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return false, errors.Wrap(err, "failed to load aws config")
 	}
 	cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
 	svc := eks.NewFromConfig(cfg)
-	result, err := svc.DescribeCluster(context.Background(), &eks.DescribeClusterInput{
+	result, err := svc.DescribeCluster(ctx, &eks.DescribeClusterInput{
 		Name: aws.String(clusterName),
 	})
 	if err != nil {
@@ -129,7 +236,10 @@ func getEKSClusterIsReady(region string, accessKeyID string, secretAccessKey str
 }
 
 // ensureEKSCluster will create the deterministic vpc for our clusters
-func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
+func ensureEKSClusterVPC(ctx context.Context, cfg aws.Config) (_ *types.AWSVPC, err error) {
+	_, span := tracing.Start(ctx, "ensure vpc", tracing.StepKey.String("vpc"))
+	defer func() { tracing.End(span, err) }()
+
 	vpc := types.AWSVPC{}
 
 	// all clusters end ip in a single VPC with a tag "replicatedhq-kubectl-grid=1"
@@ -146,7 +256,7 @@ func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
 			},
 		},
 	}
-	describeVPCsResult, err := svc.DescribeVpcs(context.Background(), describeVPCsInput)
+	describeVPCsResult, err := svc.DescribeVpcs(ctx, describeVPCsInput)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to describe VPCs")
 	}
@@ -168,7 +278,7 @@ func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
 				},
 			},
 		}
-		createVPCResult, err := svc.CreateVpc(context.Background(), createVPCInput)
+		createVPCResult, err := svc.CreateVpc(ctx, createVPCInput)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create VPC")
 		}
@@ -176,13 +286,13 @@ func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
 		vpc.ID = *createVPCResult.Vpc.VpcId
 	}
 
-	igwID, err := ensureInternetGateway(cfg, vpc.ID)
+	igwID, err := ensureInternetGateway(ctx, cfg, vpc.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure internet gateway")
 	}
 	vpc.InternetGatewayID = igwID
 
-	securityGroupID, err := ensureEKSClusterSecurityGroup(cfg, vpc.ID)
+	securityGroupID, err := ensureEKSClusterSecurityGroup(ctx, cfg, vpc.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure security group")
 	}
@@ -190,43 +300,43 @@ func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
 		securityGroupID,
 	}
 
-	privateSubnetIDs, err := ensurePrivateEKSSubnets(cfg, vpc.ID)
+	privateSubnetIDs, err := ensurePrivateEKSSubnets(ctx, cfg, vpc.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure private subnets")
 	}
 	vpc.PrivateSubnetIDs = privateSubnetIDs
 
-	publicSubnetID, err := ensurePublicEKSSubnet(cfg, vpc.ID)
+	publicSubnetID, err := ensurePublicEKSSubnet(ctx, cfg, vpc.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure public subnets")
 	}
 	vpc.PublicSubnetID = publicSubnetID
 
-	err = ensurePublicSubnetRouteTable(cfg, vpc.ID, vpc.PublicSubnetID, vpc.InternetGatewayID)
+	err = ensurePublicSubnetRouteTable(ctx, cfg, vpc.ID, vpc.PublicSubnetID, vpc.InternetGatewayID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure public subnet route table")
 	}
 
-	eipAllocationID, err := ensureElasticIP(cfg)
+	eipAllocationID, err := ensureElasticIP(ctx, cfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure elastic ip")
 	}
 	vpc.EIPAllocationID = eipAllocationID
 
-	natGatewayID, err := ensureNATGateway(cfg, vpc.PublicSubnetID, vpc.EIPAllocationID)
+	natGatewayID, err := ensureNATGateway(ctx, cfg, vpc.PublicSubnetID, vpc.EIPAllocationID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure nat gateway")
 	}
 	vpc.NATGatewayID = natGatewayID
 
 	for _, subnetID := range vpc.PrivateSubnetIDs {
-		err = ensurePrivateSubnetRouteTable(cfg, vpc.ID, subnetID, vpc.NATGatewayID)
+		err = ensurePrivateSubnetRouteTable(ctx, cfg, vpc.ID, subnetID, vpc.NATGatewayID)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to ensure private subnet route table")
 		}
 	}
 
-	roleArn, err := ensureEKSRoleARN(cfg)
+	roleArn, err := ensureEKSRoleARN(ctx, cfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to ensure role arn")
 	}
@@ -235,8 +345,7 @@ func ensureEKSClusterVPC(cfg aws.Config) (*types.AWSVPC, error) {
 	return &vpc, nil
 }
 
-func ensureInternetGateway(cfg aws.Config, vpcID string) (string, error) {
-	ctx := context.Background()
+func ensureInternetGateway(ctx context.Context, cfg aws.Config, vpcID string) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeInternetGatewaysInput := &ec2.DescribeInternetGatewaysInput{
@@ -288,7 +397,7 @@ func ensureInternetGateway(cfg aws.Config, vpcID string) (string, error) {
 	return *createInternetGatewayResult.InternetGateway.InternetGatewayId, nil
 }
 
-func ensureEKSClusterSecurityGroup(cfg aws.Config, vpcID string) (string, error) {
+func ensureEKSClusterSecurityGroup(ctx context.Context, cfg aws.Config, vpcID string) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeSecurityGroupsInput := &ec2.DescribeSecurityGroupsInput{
@@ -301,7 +410,7 @@ func ensureEKSClusterSecurityGroup(cfg aws.Config, vpcID string) (string, error)
 			},
 		},
 	}
-	describeSecurityGroupsResult, err := svc.DescribeSecurityGroups(context.Background(), describeSecurityGroupsInput)
+	describeSecurityGroupsResult, err := svc.DescribeSecurityGroups(ctx, describeSecurityGroupsInput)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to describe security groups")
 	}
@@ -325,7 +434,7 @@ func ensureEKSClusterSecurityGroup(cfg aws.Config, vpcID string) (string, error)
 			},
 		},
 	}
-	createSecurityGroupResult, err := svc.CreateSecurityGroup(context.Background(), createSecurityGroupInput)
+	createSecurityGroupResult, err := svc.CreateSecurityGroup(ctx, createSecurityGroupInput)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create security group")
 	}
@@ -333,7 +442,7 @@ func ensureEKSClusterSecurityGroup(cfg aws.Config, vpcID string) (string, error)
 	return *createSecurityGroupResult.GroupId, nil
 }
 
-func ensurePrivateEKSSubnets(cfg aws.Config, vpcID string) ([]string, error) {
+func ensurePrivateEKSSubnets(ctx context.Context, cfg aws.Config, vpcID string) ([]string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeSubnetsInput := &ec2.DescribeSubnetsInput{
@@ -347,7 +456,7 @@ func ensurePrivateEKSSubnets(cfg aws.Config, vpcID string) ([]string, error) {
 			},
 		},
 	}
-	describeSubnetsResult, err := svc.DescribeSubnets(context.Background(), describeSubnetsInput)
+	describeSubnetsResult, err := svc.DescribeSubnets(ctx, describeSubnetsInput)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to describe subnets")
 	}
@@ -366,13 +475,13 @@ func ensurePrivateEKSSubnets(cfg aws.Config, vpcID string) ([]string, error) {
 		return subnetIDs, nil
 	}
 
-	subnetID, err := createSubnetInVPC(cfg, vpcID, "172.24.100.0/24", "us-west-1a", "replicatedhq/private")
+	subnetID, err := createSubnetInVPC(ctx, cfg, vpcID, "172.24.100.0/24", "us-west-1a", "replicatedhq/private")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create subnet")
 	}
 	subnetIDs = append(subnetIDs, subnetID)
 
-	subnetID, err = createSubnetInVPC(cfg, vpcID, "172.24.101.0/24", "us-west-1b", "replicatedhq/private")
+	subnetID, err = createSubnetInVPC(ctx, cfg, vpcID, "172.24.101.0/24", "us-west-1b", "replicatedhq/private")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create subnet")
 	}
@@ -381,8 +490,7 @@ func ensurePrivateEKSSubnets(cfg aws.Config, vpcID string) ([]string, error) {
 	return subnetIDs, nil
 }
 
-func ensurePublicEKSSubnet(cfg aws.Config, vpcID string) (string, error) {
-	ctx := context.Background()
+func ensurePublicEKSSubnet(ctx context.Context, cfg aws.Config, vpcID string) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeSubnetsInput := &ec2.DescribeSubnetsInput{
@@ -407,7 +515,7 @@ func ensurePublicEKSSubnet(cfg aws.Config, vpcID string) (string, error) {
 		}
 	}
 
-	subnetID, err := createSubnetInVPC(cfg, vpcID, "172.24.102.0/24", "us-west-1a", "replicatedhq/public")
+	subnetID, err := createSubnetInVPC(ctx, cfg, vpcID, "172.24.102.0/24", "us-west-1a", "replicatedhq/public")
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create subnet")
 	}
@@ -415,8 +523,7 @@ func ensurePublicEKSSubnet(cfg aws.Config, vpcID string) (string, error) {
 	return subnetID, nil
 }
 
-func ensurePrivateSubnetRouteTable(cfg aws.Config, vpcID string, subnetID string, natGatewayID string) error {
-	ctx := context.Background()
+func ensurePrivateSubnetRouteTable(ctx context.Context, cfg aws.Config, vpcID string, subnetID string, natGatewayID string) error {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeRouteTablesInput := &ec2.DescribeRouteTablesInput{
@@ -500,8 +607,7 @@ FindRouteTable:
 	return nil
 }
 
-func ensurePublicSubnetRouteTable(cfg aws.Config, vpcID string, subnetID string, igwID string) error {
-	ctx := context.Background()
+func ensurePublicSubnetRouteTable(ctx context.Context, cfg aws.Config, vpcID string, subnetID string, igwID string) error {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeRouteTablesInput := &ec2.DescribeRouteTablesInput{
@@ -582,8 +688,7 @@ FindRouteTable:
 	return nil
 }
 
-func ensureElasticIP(cfg aws.Config) (string, error) {
-	ctx := context.Background()
+func ensureElasticIP(ctx context.Context, cfg aws.Config) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeAddressesInput := &ec2.DescribeAddressesInput{
@@ -627,8 +732,7 @@ func ensureElasticIP(cfg aws.Config) (string, error) {
 	return *allocateAddressResult.AllocationId, nil
 }
 
-func ensureNATGateway(cfg aws.Config, subnetID string, allocationID string) (string, error) {
-	ctx := context.Background()
+func ensureNATGateway(ctx context.Context, cfg aws.Config, subnetID string, allocationID string) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	describeNatGatewaysInput := &ec2.DescribeNatGatewaysInput{
@@ -674,15 +778,14 @@ func ensureNATGateway(cfg aws.Config, subnetID string, allocationID string) (str
 
 	gwID := *createNatGatewayResult.NatGateway.NatGatewayId
 
-	if err := waitForNATGateway(cfg, gwID); err != nil {
+	if err := waitForNATGateway(ctx, cfg, gwID); err != nil {
 		return "", errors.Wrap(err, "failed to wait for nat gateway")
 	}
 
 	return gwID, nil
 }
 
-func waitForNATGateway(cfg aws.Config, natGatewayID string) error {
-	ctx := context.Background()
+func waitForNATGateway(ctx context.Context, cfg aws.Config, natGatewayID string) error {
 	svc := ec2.NewFromConfig(cfg)
 
 	for i := 0; i < 10; i++ {
@@ -699,13 +802,17 @@ func waitForNATGateway(cfg aws.Config, natGatewayID string) error {
 			}
 		}
 
-		time.Sleep(10 * time.Second)
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return errors.New("timed out")
 }
 
-func createSubnetInVPC(cfg aws.Config, vpcID string, cidrBlock string, az string, tag string) (string, error) {
+func createSubnetInVPC(ctx context.Context, cfg aws.Config, vpcID string, cidrBlock string, az string, tag string) (string, error) {
 	svc := ec2.NewFromConfig(cfg)
 
 	createSubnetInput := &ec2.CreateSubnetInput{
@@ -728,7 +835,7 @@ func createSubnetInVPC(cfg aws.Config, vpcID string, cidrBlock string, az string
 			},
 		},
 	}
-	createSubnetResult, err := svc.CreateSubnet(context.Background(), createSubnetInput)
+	createSubnetResult, err := svc.CreateSubnet(ctx, createSubnetInput)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create subnet")
 	}
@@ -736,14 +843,14 @@ func createSubnetInVPC(cfg aws.Config, vpcID string, cidrBlock string, az string
 	return *createSubnetResult.Subnet.SubnetId, nil
 }
 
-func ensureEKSRoleARN(cfg aws.Config) (string, error) {
+func ensureEKSRoleARN(ctx context.Context, cfg aws.Config) (string, error) {
 	svc := iam.NewFromConfig(cfg)
 
 	listRolesInput := &iam.ListRolesInput{
 		PathPrefix: aws.String("/replicatedhq/"),
 	}
 
-	listRolesResult, err := svc.ListRoles(context.Background(), listRolesInput)
+	listRolesResult, err := svc.ListRoles(ctx, listRolesInput)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to list roles")
 	}
@@ -781,34 +888,34 @@ func ensureEKSRoleARN(cfg aws.Config) (string, error) {
 		Path:                     aws.String("/replicatedhq/"),
 		AssumeRolePolicyDocument: aws.String(string(rolePolicy)),
 	}
-	result, err := svc.CreateRole(context.Background(), &createRoleInput)
+	result, err := svc.CreateRole(ctx, &createRoleInput)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create role")
 	}
 
-	if err := attachRolePolicy(cfg, "arn:aws:iam::aws:policy/AmazonEKSClusterPolicy"); err != nil {
+	if err := attachRolePolicy(ctx, cfg, "arn:aws:iam::aws:policy/AmazonEKSClusterPolicy"); err != nil {
 		return "", errors.Wrap(err, "failed to attach policy 1")
 	}
-	if err := attachRolePolicy(cfg, "arn:aws:iam::aws:policy/AmazonEKSServicePolicy"); err != nil {
+	if err := attachRolePolicy(ctx, cfg, "arn:aws:iam::aws:policy/AmazonEKSServicePolicy"); err != nil {
 		return "", errors.Wrap(err, "failed to attach policy 2")
 	}
-	if err := attachRolePolicy(cfg, "arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy"); err != nil {
+	if err := attachRolePolicy(ctx, cfg, "arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy"); err != nil {
 		return "", errors.Wrap(err, "failed to attach policy 3")
 	}
-	if err := attachRolePolicy(cfg, "arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly"); err != nil {
+	if err := attachRolePolicy(ctx, cfg, "arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly"); err != nil {
 		return "", errors.Wrap(err, "failed to attach policy 4")
 	}
-	if err := attachRolePolicy(cfg, "arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"); err != nil {
+	if err := attachRolePolicy(ctx, cfg, "arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"); err != nil {
 		return "", errors.Wrap(err, "failed to attach policy 5")
 	}
 
 	return *result.Role.Arn, nil
 }
 
-func attachRolePolicy(cfg aws.Config, policyName string) error {
+func attachRolePolicy(ctx context.Context, cfg aws.Config, policyName string) error {
 	svc := iam.NewFromConfig(cfg)
 
-	_, err := svc.AttachRolePolicy(context.Background(), &iam.AttachRolePolicyInput{
+	_, err := svc.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
 		PolicyArn: aws.String(policyName),
 		RoleName:  aws.String("kubectl-grid"),
 	})
@@ -823,7 +930,10 @@ func attachRolePolicy(cfg aws.Config, policyName string) error {
 	return nil
 }
 
-func ensureEKSCluterControlPlane(cfg aws.Config, newEKSCluster *types.EKSNewClusterSpec, clusterName string, vpc *types.AWSVPC) (*ekstypes.Cluster, error) {
+func ensureEKSCluterControlPlane(ctx context.Context, cfg aws.Config, newEKSCluster *types.EKSNewClusterSpec, clusterName string, vpc *types.AWSVPC) (_ *ekstypes.Cluster, err error) {
+	_, span := tracing.Start(ctx, "ensure control plane", tracing.StepKey.String("control plane"))
+	defer func() { tracing.End(span, err) }()
+
 	svc := eks.NewFromConfig(cfg)
 
 	version := newEKSCluster.Version
@@ -842,7 +952,7 @@ func ensureEKSCluterControlPlane(cfg aws.Config, newEKSCluster *types.EKSNewClus
 		Version: aws.String(version),
 	}
 
-	createdCluster, err := svc.CreateCluster(context.Background(), input)
+	createdCluster, err := svc.CreateCluster(ctx, input)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create eks custer")
 	}
@@ -850,43 +960,61 @@ func ensureEKSCluterControlPlane(cfg aws.Config, newEKSCluster *types.EKSNewClus
 	return createdCluster.Cluster, nil
 }
 
-func waitForClusterToBeActive(newEKSCluster *types.EKSNewClusterSpec, accessKeyID string, secretAccessKey string, clusterName string) error {
-	resultCh := make(chan string)
-	keepTrying := true
-	go func() {
-		for keepTrying {
-			isReady, err := getEKSClusterIsReady(newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
-			if err != nil {
-				resultCh <- fmt.Sprintf("error checking cluster status: %s", err.Error())
-				return
-			}
+func waitForClusterToBeActive(ctx context.Context, newEKSCluster *types.EKSNewClusterSpec, accessKeyID string, secretAccessKey string, clusterName string) (err error) {
+	_, span := tracing.Start(ctx, "wait for control plane", tracing.StepKey.String("control plane"))
+	defer func() { tracing.End(span, err) }()
 
-			if isReady {
-				keepTrying = false
-				resultCh <- ""
-				return
-			}
+	deadline := time.Now().Add(20 * time.Minute)
+	for time.Now().Before(deadline) {
+		isReady, err := getEKSClusterIsReady(ctx, newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
+		if err != nil {
+			return errors.Wrap(err, "error checking cluster status")
+		}
+		if isReady {
+			return nil
+		}
 
-			time.Sleep(time.Second * 9)
+		select {
+		case <-time.After(9 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-	}()
+	}
 
-	select {
-	case res := <-resultCh:
-		if res != "" {
-			return errors.New(res)
+	return errors.New("timeout waiting for cluster")
+}
+
+func waitForNodeGroupToBeActive(ctx context.Context, newEKSCluster *types.EKSNewClusterSpec, accessKeyID string, secretAccessKey string, clusterName string) (err error) {
+	_, span := tracing.Start(ctx, "wait for node group", tracing.StepKey.String("node group"))
+	defer func() { tracing.End(span, err) }()
+
+	deadline := time.Now().Add(20 * time.Minute)
+	for time.Now().Before(deadline) {
+		isReady, err := GetEKSClusterNodePoolIsReady(ctx, newEKSCluster.Region, accessKeyID, secretAccessKey, clusterName)
+		if err != nil {
+			return errors.Wrap(err, "failed to check node group status")
+		}
+		if isReady {
+			return nil
+		}
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-		return nil
-	case <-time.After(20 * time.Minute):
-		keepTrying = false
-		return errors.New("timeout waiting for cluster")
 	}
+
+	return errors.New("timeout waiting for node group")
 }
 
-func ensureEKSClusterNodeGroup(cfg aws.Config, cluster *ekstypes.Cluster, clusterName string, vpc *types.AWSVPC) (*ekstypes.Nodegroup, error) {
+func ensureEKSClusterNodeGroup(ctx context.Context, cfg aws.Config, cluster *ekstypes.Cluster, clusterName string, vpc *types.AWSVPC) (_ *ekstypes.Nodegroup, err error) {
+	_, span := tracing.Start(ctx, "ensure node group", tracing.StepKey.String("node group"))
+	defer func() { tracing.End(span, err) }()
+
 	svc := eks.NewFromConfig(cfg)
 
-	nodeGroup, err := svc.CreateNodegroup(context.Background(), &eks.CreateNodegroupInput{
+	nodeGroup, err := svc.CreateNodegroup(ctx, &eks.CreateNodegroupInput{
 		ClusterName:   aws.String(clusterName),
 		NodeRole:      aws.String(vpc.RoleArn),
 		NodegroupName: aws.String(clusterName),
@@ -899,14 +1027,14 @@ func ensureEKSClusterNodeGroup(cfg aws.Config, cluster *ekstypes.Cluster, cluste
 	return nodeGroup.Nodegroup, nil
 }
 
-func deleteEKSNodeGroup(cfg aws.Config, clusterName string, groupName string) error {
+func deleteEKSNodeGroup(ctx context.Context, cfg aws.Config, clusterName string, groupName string) error {
 	svc := eks.NewFromConfig(cfg)
 
 	deleteNodegroupInput := &eks.DeleteNodegroupInput{
 		ClusterName:   aws.String(clusterName),
 		NodegroupName: aws.String(groupName),
 	}
-	_, err := svc.DeleteNodegroup(context.Background(), deleteNodegroupInput)
+	_, err := svc.DeleteNodegroup(ctx, deleteNodegroupInput)
 	if err != nil && !isEKSNotFound(err) {
 		return errors.Wrap(err, "failed to delete node group")
 	}
@@ -914,7 +1042,7 @@ func deleteEKSNodeGroup(cfg aws.Config, clusterName string, groupName string) er
 	return nil
 }
 
-func waitEKSNodeGroupGone(cfg aws.Config, clusterName string, groupName string) error {
+func waitEKSNodeGroupGone(ctx context.Context, cfg aws.Config, clusterName string, groupName string) error {
 	svc := eks.NewFromConfig(cfg)
 
 	for i := 0; i < 24; i++ {
@@ -922,7 +1050,7 @@ func waitEKSNodeGroupGone(cfg aws.Config, clusterName string, groupName string)
 			ClusterName:   aws.String(clusterName),
 			NodegroupName: aws.String(groupName),
 		}
-		_, err := svc.DescribeNodegroup(context.Background(), describeNodegroupInput)
+		_, err := svc.DescribeNodegroup(ctx, describeNodegroupInput)
 		if err != nil {
 			if isEKSNotFound(err) {
 				return nil
@@ -930,20 +1058,24 @@ func waitEKSNodeGroupGone(cfg aws.Config, clusterName string, groupName string)
 			return errors.Wrap(err, "failed to describe node group")
 		}
 
-		time.Sleep(10 * time.Second)
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return errors.New("timed out")
 }
 
-func deleteEKSCluster(cfg aws.Config, clusterName string) error {
+func deleteEKSCluster(ctx context.Context, cfg aws.Config, clusterName string) error {
 	svc := eks.NewFromConfig(cfg)
 
 	deleteClusterInput := &eks.DeleteClusterInput{
 		Name: aws.String(clusterName),
 	}
 
-	_, err := svc.DeleteCluster(context.Background(), deleteClusterInput)
+	_, err := svc.DeleteCluster(ctx, deleteClusterInput)
 	if err != nil {
 		if isEKSNotFound(err) {
 			return nil
@@ -953,3 +1085,10 @@ func deleteEKSCluster(cfg aws.Config, clusterName string) error {
 
 	return nil
 }
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,49 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eksCredentialsFromKubeconfig(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+clusters:
+- cluster:
+    server: https://example.eks.amazonaws.com
+  name: kubernetes
+contexts:
+- context:
+    cluster: kubernetes
+    user: aws
+  name: aws
+current-context: aws
+kind: Config
+users:
+- name: aws
+  user:
+    exec:
+        apiVersion: client.authentication.k8s.io/v1alpha1
+        command: aws
+        args:
+        - "eks"
+        - "get-token"
+        env:
+        - name: AWS_ACCESS_KEY_ID
+          value: AKIAEXAMPLE
+        - name: AWS_SECRET_ACCESS_KEY
+          value: secret
+`
+
+	accessKeyID, secretAccessKey, err := eksCredentialsFromKubeconfig(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, "AKIAEXAMPLE", accessKeyID)
+	assert.Equal(t, "secret", secretAccessKey)
+
+	_, _, err = eksCredentialsFromKubeconfig(`apiVersion: v1
+kind: Config
+current-context: missing
+`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package grid
+
+import (
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// updateGridConfig will load the config, call fn with the named grid and save the config
+func updateGridConfig(configFilePath string, gridName string, fn func(g *types.GridConfig) error) error {
+	lockConfig()
+	defer unlockConfig()
+
+	c, err := loadConfig(configFilePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	var gridConfig *types.GridConfig
+	for _, g := range c.GridConfigs {
+		if g.Name == gridName {
+			gridConfig = g
+		}
+	}
+	if gridConfig == nil {
+		return errors.Errorf("grid %s not found", gridName)
+	}
+
+	if err := fn(gridConfig); err != nil {
+		return err
+	}
+
+	if err := saveConfig(c, configFilePath); err != nil {
+		return errors.Wrap(err, "failed to save config")
+	}
+
+	return nil
+}
+
+func setGridPhase(configFilePath string, gridName string, phase string) error {
+	return updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		g.Phase = phase
+		return nil
+	})
+}
+
+// recordPartialClusterResource is called before a resource is created for a cluster, so that
+// it can be found if the create is interrupted
+func recordPartialClusterResource(configFilePath string, gridName string, partial types.PartialClusterConfig, resource string) error {
+	return updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		for _, p := range g.PartialClusters {
+			if p.Name != partial.Name {
+				continue
+			}
+			for _, r := range p.Resources {
+				if r == resource {
+					return nil
+				}
+			}
+			p.Resources = append(p.Resources, resource)
+			return nil
+		}
+
+		partial.Resources = []string{resource}
+		g.PartialClusters = append(g.PartialClusters, &partial)
+		return nil
+	})
+}
+
+// removePartialCluster is called when the cluster is completely created
+func removePartialCluster(configFilePath string, gridName string, clusterName string) error {
+	return updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		partialClusters := []*types.PartialClusterConfig{}
+		for _, p := range g.PartialClusters {
+			if p.Name != clusterName {
+				partialClusters = append(partialClusters, p)
+			}
+		}
+		g.PartialClusters = partialClusters
+		return nil
+	})
+}
+
+// resumeGridInConfig will mark a grid that was not completely created as creating again, and
+// return the names of the clusters that don't need to be created
+func resumeGridInConfig(configFilePath string, gridName string) (map[string]bool, error) {
+	completed := map[string]bool{}
+	err := updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		if g.Phase == "" || g.Phase == types.GridPhaseReady {
+			return errors.Errorf("grid %s was completely created, there is nothing to resume", gridName)
+		}
+
+		for _, c := range g.ClusterConfigs {
+			if c.GetPhase() == types.ClusterPhaseReady {
+				completed[c.Name] = true
+			}
+		}
+
+		g.Phase = types.GridPhaseCreating
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completed, nil
+}
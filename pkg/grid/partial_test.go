@@ -0,0 +1,67 @@
+package grid
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_partialClusters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grid")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFilePath := filepath.Join(dir, "config")
+
+	// grid-2 was completely created, grid-1 was added to the config before it was interrupted
+	require.NoError(t, saveConfig(&types.GridsConfig{
+		GridConfigs: []*types.GridConfig{
+			{
+				Name:  "grid",
+				Phase: types.GridPhaseCreating,
+				ClusterConfigs: []*types.ClusterConfig{
+					{Name: "grid-1", Status: &types.ClusterStatus{Phase: types.ClusterPhaseProvisioning}},
+					{Name: "grid-2", Status: &types.ClusterStatus{Phase: types.ClusterPhaseReady}},
+					{Name: "grid-3", Status: &types.ClusterStatus{Phase: types.ClusterPhasePending}},
+				},
+			},
+		},
+	}, configFilePath))
+
+	partial := types.PartialClusterConfig{
+		Name:     "grid-1",
+		Provider: "aws",
+		Region:   "us-east-1",
+	}
+	require.NoError(t, recordPartialClusterResource(configFilePath, "grid", partial, types.ClusterResourceControlPlane))
+	require.NoError(t, recordPartialClusterResource(configFilePath, "grid", partial, types.ClusterResourceNodeGroup))
+	require.NoError(t, recordPartialClusterResource(configFilePath, "grid", partial, types.ClusterResourceNodeGroup))
+
+	require.NoError(t, setGridPhase(configFilePath, "grid", types.GridPhaseInterrupted))
+
+	c, err := loadConfig(configFilePath)
+	require.NoError(t, err)
+	require.Len(t, c.GridConfigs, 1)
+	assert.Equal(t, types.GridPhaseInterrupted, c.GridConfigs[0].Phase)
+	require.Len(t, c.GridConfigs[0].PartialClusters, 1)
+	assert.Equal(t, []string{types.ClusterResourceControlPlane, types.ClusterResourceNodeGroup}, c.GridConfigs[0].PartialClusters[0].Resources)
+
+	completed, err := resumeGridInConfig(configFilePath, "grid")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"grid-2": true}, completed)
+
+	require.NoError(t, removePartialCluster(configFilePath, "grid", "grid-1"))
+	require.NoError(t, setGridPhase(configFilePath, "grid", types.GridPhaseReady))
+
+	c, err = loadConfig(configFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, types.GridPhaseReady, c.GridConfigs[0].Phase)
+	assert.Empty(t, c.GridConfigs[0].PartialClusters)
+
+	_, err = resumeGridInConfig(configFilePath, "grid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package grid
+
+import (
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// updateClusterConfig will load the config, call fn with the named cluster and save the config
+func updateClusterConfig(configFilePath string, gridName string, clusterName string, fn func(c *types.ClusterConfig)) error {
+	return updateGridConfig(configFilePath, gridName, func(g *types.GridConfig) error {
+		for _, c := range g.ClusterConfigs {
+			if c.Name == clusterName {
+				fn(c)
+				return nil
+			}
+		}
+
+		return errors.Errorf("cluster %s not found", clusterName)
+	})
+}
+
+func setClusterPhase(configFilePath string, gridName string, clusterName string, phase string, message string) error {
+	return updateClusterConfig(configFilePath, gridName, clusterName, func(c *types.ClusterConfig) {
+		c.SetPhase(phase, message)
+	})
+}
+
+func setClusterCondition(configFilePath string, gridName string, clusterName string, conditionType string) error {
+	return updateClusterConfig(configFilePath, gridName, clusterName, func(c *types.ClusterConfig) {
+		c.SetCondition(conditionType, types.ConditionStatusTrue, "")
+	})
+}
@@ -13,13 +13,82 @@ type Application struct {
 
 type ApplicationSpec struct {
 	KOTSApplicationSpec *KOTSApplicationSpec `json:"kots,omitempty"`
+
+	// Checks are evaluated on every cluster after the application is ready. Each check
+	// is retried until it passes or CheckTimeout is reached
+	Checks       []ApplicationCheck `json:"checks,omitempty"`
+	CheckTimeout string             `json:"checkTimeout,omitempty"`
+}
+
+// ApplicationCheck should have exactly one check set
+type ApplicationCheck struct {
+	Name               string                   `json:"name,omitempty"`
+	HTTPGet            *HTTPGetCheck            `json:"httpGet,omitempty"`
+	ReadyEndpoints     *ReadyEndpointsCheck     `json:"readyEndpoints,omitempty"`
+	NoCrashLoopBackOff *NoCrashLoopBackOffCheck `json:"noCrashLoopBackOff,omitempty"`
+}
+
+// HTTPGetCheck requests URL, which is a go template rendered with the cluster config, for
+// example through an ingress. When URL is not set, Path is requested from Service (name:port)
+// through the api server proxy
+type HTTPGetCheck struct {
+	URL            string `json:"url,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	Service        string `json:"service,omitempty"`
+	Path           string `json:"path,omitempty"`
+	ExpectedStatus int    `json:"expectedStatus,omitempty"`
+	BodyRegex      string `json:"bodyRegex,omitempty"`
+}
+
+// ReadyEndpointsCheck passes when the service has at least Count ready endpoints
+type ReadyEndpointsCheck struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	Count     int    `json:"count"`
+}
+
+type NoCrashLoopBackOffCheck struct {
+	Namespace string `json:"namespace"`
 }
 
 type KOTSApplicationSpec struct {
-	Version        string                        `json:"version,omitempty"`
-	App            string                        `json:"app"`
-	LicenseID      string                        `json:"licenseID"`
-	SkipPreflights *bool                         `json:"skipPreflights,omitempty"`
-	Namespace      string                        `json:"namespace,omitempty"`
-	ConfigValues   *kotsv1beta1.ConfigValuesSpec `json:"configValues,omitempty"`
+	Version             string                        `json:"version,omitempty"`
+	DownloadURL         string                        `json:"downloadURL,omitempty"`
+	App                 string                        `json:"app"`
+	LicenseID           string                        `json:"licenseID,omitempty"`
+	License             *ValueOrValueFrom             `json:"license,omitempty"`
+	LicenseEndpoint     string                        `json:"licenseEndpoint,omitempty"`
+	SkipPreflights      *bool                         `json:"skipPreflights,omitempty"`
+	FailOnPreflightWarn bool                          `json:"failOnPreflightWarn,omitempty"`
+	FailOnPreflightFail bool                          `json:"failOnPreflightFail,omitempty"`
+	Namespace           string                        `json:"namespace,omitempty"`
+	ConfigValues        *kotsv1beta1.ConfigValuesSpec `json:"configValues,omitempty"`
+
+	// ConfigValuesFrom and ClusterConfigValues (keyed by cluster name) are item name to value
+	// maps that override ConfigValues. All config values can use go templates with the
+	// cluster config, for example {{ .Name }} or {{ .Region }}
+	ConfigValuesFrom    map[string]ValueOrValueFrom            `json:"configValuesFrom,omitempty"`
+	ClusterConfigValues map[string]map[string]ValueOrValueFrom `json:"clusterConfigValues,omitempty"`
+
+	Airgap       *KOTSAirgapSpec       `json:"airgap,omitempty"`
+	AdminConsole *KOTSAdminConsoleSpec `json:"adminConsole,omitempty"`
+}
+
+type KOTSAdminConsoleSpec struct {
+	SharedPassword *ValueOrValueFrom `json:"sharedPassword,omitempty"`
+	WaitDuration   string            `json:"waitDuration,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+}
+
+type KOTSAirgapSpec struct {
+	BundlePath        string           `json:"bundlePath"`
+	KotsadmBundlePath string           `json:"kotsadmBundlePath,omitempty"`
+	Registry          KOTSRegistrySpec `json:"registry"`
+}
+
+type KOTSRegistrySpec struct {
+	Hostname  ValueOrValueFrom `json:"hostname"`
+	Namespace ValueOrValueFrom `json:"namespace"`
+	Username  ValueOrValueFrom `json:"username"`
+	Password  ValueOrValueFrom `json:"password"`
 }
@@ -3,15 +3,42 @@ package types
 import (
 	"crypto/md5"
 	"fmt"
+	"time"
 )
 
 type GridsConfig struct {
 	GridConfigs []*GridConfig `json:"grids,omitempty"`
 }
 
+const (
+	GridPhaseCreating    = "Creating"
+	GridPhaseReady       = "Ready"
+	GridPhaseFailed      = "Failed"
+	GridPhaseInterrupted = "Interrupted"
+)
+
 type GridConfig struct {
 	Name           string           `json:"name"`
+	Phase          string           `json:"phase,omitempty"`
 	ClusterConfigs []*ClusterConfig `json:"clusters,omitempty"`
+	// PartialClusters are the clusters that have not finished being created
+	PartialClusters []*PartialClusterConfig `json:"partialClusters,omitempty"`
+}
+
+// The resources that are created for each cluster. The vpc, subnets and role are shared by
+// every grid, so they're not recorded
+const (
+	ClusterResourceControlPlane = "controlPlane"
+	ClusterResourceNodeGroup    = "nodeGroup"
+)
+
+// PartialClusterConfig records the resources that were created for a cluster before
+// the create finished, so that they can be rolled back or the create can be resumed
+type PartialClusterConfig struct {
+	Name      string   `json:"name"`
+	Provider  string   `json:"provider"`
+	Region    string   `json:"region"`
+	Resources []string `json:"resources,omitempty"`
 }
 
 type ClusterConfig struct {
@@ -22,6 +49,88 @@ type ClusterConfig struct {
 	Kubeconfig  string `json:"kubeconfig,omitempty"`
 	Description string `json:"description,omitempty"`
 	Version     string `json:"version,omitempty"`
+
+	Status *ClusterStatus `json:"status,omitempty"`
+	// CreatedAt is not set for clusters that were created before it was recorded
+	CreatedAt *time.Time        `json:"createdAt,omitempty"`
+	EKS       *EKSClusterConfig `json:"eks,omitempty"`
+
+	AdminConsole     *AdminConsoleConfig   `json:"adminConsole,omitempty"`
+	Applications     []DeployedApplication `json:"applications,omitempty"`
+	PreflightResults []PreflightResult     `json:"preflightResults,omitempty"`
+	CheckResults     []CheckResult         `json:"checkResults,omitempty"`
+}
+
+// EKSClusterConfig records the AWS resources of an EKS cluster
+type EKSClusterConfig struct {
+	Endpoint         string   `json:"endpoint,omitempty"`
+	VPCID            string   `json:"vpcId,omitempty"`
+	SubnetIDs        []string `json:"subnetIds,omitempty"`
+	SecurityGroupIDs []string `json:"securityGroupIds,omitempty"`
+	NodeGroups       []string `json:"nodeGroups,omitempty"`
+}
+
+// DeployedApplication is an application that was deployed to the cluster
+type DeployedApplication struct {
+	Name       string    `json:"name"`
+	DeployedAt time.Time `json:"deployedAt"`
+}
+
+// AdminConsoleConfig records where the admin console was installed on the cluster.
+// SharedPassword is only set when the password was generated during deploy
+type AdminConsoleConfig struct {
+	Namespace      string `json:"namespace"`
+	SharedPassword string `json:"sharedPassword,omitempty"`
+}
+
+const (
+	PreflightStatePass = "pass"
+	PreflightStateWarn = "warn"
+	PreflightStateFail = "fail"
+)
+
+type PreflightResult struct {
+	Check   string `json:"check"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	CheckStatePass = "pass"
+	CheckStateFail = "fail"
+)
+
+// CheckResult is the result of an application check on a cluster, after retries
+type CheckResult struct {
+	Check   string `json:"check"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+// SetDeployedApplication will record that the application was deployed to the cluster
+func (c *ClusterConfig) SetDeployedApplication(name string) {
+	for i, a := range c.Applications {
+		if a.Name == name {
+			c.Applications[i].DeployedAt = time.Now()
+			return
+		}
+	}
+
+	c.Applications = append(c.Applications, DeployedApplication{
+		Name:       name,
+		DeployedAt: time.Now(),
+	})
+}
+
+// RemoveDeployedApplication will remove the application from the applications deployed to the cluster
+func (c *ClusterConfig) RemoveDeployedApplication(name string) {
+	applications := []DeployedApplication{}
+	for _, a := range c.Applications {
+		if a.Name != name {
+			applications = append(applications, a)
+		}
+	}
+	c.Applications = applications
 }
 
 func (c ClusterConfig) GetDeterministicClusterName() string {
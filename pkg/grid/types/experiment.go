@@ -0,0 +1,93 @@
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Experiment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ExperimentSpec `json:"spec"`
+}
+
+type ExperimentSpec struct {
+	K6    *K6ExperimentSpec    `json:"k6,omitempty"`
+	Job   *JobExperimentSpec   `json:"job,omitempty"`
+	Chaos *ChaosExperimentSpec `json:"chaos,omitempty"`
+}
+
+// K6ExperimentSpec runs a k6 script as a job in each cluster. Target is a go template
+// rendered with the cluster config, and is passed to the script as the TARGET_URL env var
+type K6ExperimentSpec struct {
+	Version   string `json:"version,omitempty"`
+	Script    string `json:"script"`
+	VUs       int    `json:"vus,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+	Target    string `json:"target,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// JobExperimentSpec runs a test image as a job in each cluster. Env values are go
+// templates rendered with the cluster config
+type JobExperimentSpec struct {
+	Image              string            `json:"image"`
+	Command            []string          `json:"command,omitempty"`
+	Env                map[string]string `json:"env,omitempty"`
+	ServiceAccountName string            `json:"serviceAccountName,omitempty"`
+	Timeout            string            `json:"timeout,omitempty"`
+	Retries            int               `json:"retries,omitempty"`
+	Namespace          string            `json:"namespace,omitempty"`
+	JUnit              *JUnitResultsSpec `json:"junit,omitempty"`
+}
+
+// JUnitResultsSpec is where the job writes junit xml results. Path is a file in the
+// container, which requires a shell in the image. ConfigMap is a config map the job
+// creates in its namespace, with the results in ConfigMapKey
+type JUnitResultsSpec struct {
+	Path         string `json:"path,omitempty"`
+	ConfigMap    string `json:"configMap,omitempty"`
+	ConfigMapKey string `json:"configMapKey,omitempty"`
+}
+
+// ChaosExperimentSpec runs each action in order on every cluster, and after each
+// action waits for the application to report ready. Application is the path to the
+// application manifest that was deployed to the grid
+type ChaosExperimentSpec struct {
+	Application  string        `json:"application"`
+	ReadyTimeout string        `json:"readyTimeout,omitempty"`
+	Actions      []ChaosAction `json:"actions"`
+}
+
+// ChaosAction should have exactly one action set
+type ChaosAction struct {
+	DrainNode        *DrainNodeAction        `json:"drainNode,omitempty"`
+	DeletePods       *DeletePodsAction       `json:"deletePods,omitempty"`
+	ScaleDeployment  *ScaleDeploymentAction  `json:"scaleDeployment,omitempty"`
+	RestartNodeGroup *RestartNodeGroupAction `json:"restartNodeGroup,omitempty"`
+}
+
+// DrainNodeAction drains a random ready node that matches the selector. The node is
+// uncordoned after the readiness check. Draining requires kubectl 1.20 or later
+type DrainNodeAction struct {
+	Selector string `json:"selector,omitempty"`
+}
+
+type DeletePodsAction struct {
+	Namespace string `json:"namespace"`
+	Selector  string `json:"selector"`
+}
+
+// ScaleDeploymentAction scales the deployment to zero, waits for Duration, and scales
+// it back to the original number of replicas
+type ScaleDeploymentAction struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Duration  string `json:"duration,omitempty"`
+}
+
+// RestartNodeGroupAction replaces each node in an EKS node group, one at a time, with the
+// AWS credentials the grid was created with. The node group defaults to the name of the
+// cluster, which is the name of node groups in clusters created by grid
+type RestartNodeGroupAction struct {
+	NodeGroup string `json:"nodeGroup,omitempty"`
+}
@@ -42,13 +42,40 @@ type EKSNewClusterSpec struct {
 	Region          string           `json:"region"`
 }
 
+// LoggerSpec configures where progress is logged. Progress is always written to the terminal,
+// as text or JSON, and also sent to Slack and to each webhook that is configured
 type LoggerSpec struct {
-	Slack *SlackLoggerSpec `json:"slack,omitempty"`
+	Slack    *SlackLoggerSpec    `json:"slack,omitempty"`
+	JSON     *JSONLoggerSpec     `json:"json,omitempty"`
+	Webhooks []WebhookLoggerSpec `json:"webhooks,omitempty"`
 }
 
 type SlackLoggerSpec struct {
 	Token   ValueOrValueFrom `json:"token,omitempty"`
 	Channel ValueOrValueFrom `json:"channel,omitempty"`
+	// FailureUserGroupID is the id of a user group, such as S0123456789, to mention when any cluster fails
+	FailureUserGroupID string `json:"failureUserGroupId,omitempty"`
+}
+
+const (
+	WebhookFormatJSON    = "json"
+	WebhookFormatTeams   = "teams"
+	WebhookFormatDiscord = "discord"
+)
+
+// WebhookLoggerSpec posts each log event to a url
+type WebhookLoggerSpec struct {
+	URL     ValueOrValueFrom            `json:"url"`
+	Headers map[string]ValueOrValueFrom `json:"headers,omitempty"`
+	// Format is json (the default) to post each event as a JSON object, or teams or discord
+	// to post a message to a Microsoft Teams or Discord incoming webhook
+	Format string `json:"format,omitempty"`
+	// Retries is the number of times to retry a failed post, with exponential backoff. Defaults to 3
+	Retries *int `json:"retries,omitempty"`
+}
+
+// JSONLoggerSpec writes each log event to stdout as a JSON object, one per line
+type JSONLoggerSpec struct {
 }
 
 func (c EKSNewClusterSpec) GetDeterministicClusterName() string {
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	ClusterPhasePending      = "Pending"
+	ClusterPhaseProvisioning = "Provisioning"
+	ClusterPhaseReady        = "Ready"
+	ClusterPhaseFailed       = "Failed"
+	ClusterPhaseDeleting     = "Deleting"
+)
+
+const (
+	ClusterConditionVPCReady           = "VPCReady"
+	ClusterConditionControlPlaneActive = "ControlPlaneActive"
+	ClusterConditionNodeGroupActive    = "NodeGroupActive"
+	ClusterConditionNodesReady         = "NodesReady"
+	ClusterConditionAppDeployed        = "AppDeployed"
+)
+
+const (
+	ConditionStatusTrue  = "True"
+	ConditionStatusFalse = "False"
+)
+
+type ClusterStatus struct {
+	Phase string `json:"phase"`
+	// Message is the reason the cluster failed
+	Message    string             `json:"message,omitempty"`
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+}
+
+type ClusterCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+	Message            string    `json:"message,omitempty"`
+}
+
+// GetPhase returns the phase of the cluster. Clusters that were created before the
+// status was recorded are ready
+func (c ClusterConfig) GetPhase() string {
+	if c.Status == nil {
+		return ClusterPhaseReady
+	}
+
+	return c.Status.Phase
+}
+
+// SetPhase will set the phase of the cluster, and the message when the cluster failed
+func (c *ClusterConfig) SetPhase(phase string, message string) {
+	if c.Status == nil {
+		c.Status = &ClusterStatus{}
+	}
+
+	c.Status.Phase = phase
+	c.Status.Message = message
+}
+
+// SetCondition will add or update the condition. The transition time only changes when
+// the status of the condition changes
+func (c *ClusterConfig) SetCondition(conditionType string, status string, message string) {
+	if c.Status == nil {
+		c.Status = &ClusterStatus{
+			Phase: ClusterPhaseReady,
+		}
+	}
+
+	for i, condition := range c.Status.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+
+		if condition.Status != status {
+			c.Status.Conditions[i].LastTransitionTime = time.Now()
+		}
+		c.Status.Conditions[i].Status = status
+		c.Status.Conditions[i].Message = message
+		return
+	}
+
+	c.Status.Conditions = append(c.Status.Conditions, ClusterCondition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: time.Now(),
+		Message:            message,
+	})
+}
+
+// RemoveCondition will remove the condition, if it has been set
+func (c *ClusterConfig) RemoveCondition(conditionType string) {
+	if c.Status == nil {
+		return
+	}
+
+	conditions := []ClusterCondition{}
+	for _, condition := range c.Status.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	c.Status.Conditions = conditions
+}
+
+// GetCondition returns the condition with the type, or nil when it has not been set
+func (c ClusterConfig) GetCondition(conditionType string) *ClusterCondition {
+	if c.Status == nil {
+		return nil
+	}
+
+	for i, condition := range c.Status.Conditions {
+		if condition.Type == conditionType {
+			return &c.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// ReadyClusters returns the clusters in the grid that are ready and have a kubeconfig.
+// Clusters that are pending or failed are recorded in the grid, but cannot be used
+func (g GridConfig) ReadyClusters() []*ClusterConfig {
+	clusters := []*ClusterConfig{}
+	for _, c := range g.ClusterConfigs {
+		if c.GetPhase() == ClusterPhaseReady && c.Kubeconfig != "" {
+			clusters = append(clusters, c)
+		}
+	}
+
+	return clusters
+}
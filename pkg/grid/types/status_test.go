@@ -0,0 +1,66 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetCondition(t *testing.T) {
+	c := ClusterConfig{}
+	assert.Equal(t, ClusterPhaseReady, c.GetPhase())
+	assert.Nil(t, c.GetCondition(ClusterConditionAppDeployed))
+
+	c.SetCondition(ClusterConditionAppDeployed, ConditionStatusFalse, "failed")
+	condition := c.GetCondition(ClusterConditionAppDeployed)
+	require.NotNil(t, condition)
+	assert.Equal(t, ConditionStatusFalse, condition.Status)
+	firstTransition := condition.LastTransitionTime
+
+	// the transition time only changes when the status changes
+	c.SetCondition(ClusterConditionAppDeployed, ConditionStatusFalse, "failed again")
+	condition = c.GetCondition(ClusterConditionAppDeployed)
+	assert.Equal(t, firstTransition, condition.LastTransitionTime)
+	assert.Equal(t, "failed again", condition.Message)
+
+	c.SetCondition(ClusterConditionAppDeployed, ConditionStatusTrue, "")
+	condition = c.GetCondition(ClusterConditionAppDeployed)
+	assert.Equal(t, ConditionStatusTrue, condition.Status)
+	assert.False(t, condition.LastTransitionTime.Before(firstTransition))
+	assert.Len(t, c.Status.Conditions, 1)
+
+	c.SetPhase(ClusterPhaseFailed, "nodes did not join")
+	assert.Equal(t, ClusterPhaseFailed, c.GetPhase())
+	assert.Equal(t, "nodes did not join", c.Status.Message)
+}
+
+func Test_RemoveCondition(t *testing.T) {
+	c := ClusterConfig{}
+	c.RemoveCondition(ClusterConditionAppDeployed)
+	assert.Nil(t, c.Status)
+
+	c.SetCondition(ClusterConditionNodesReady, ConditionStatusTrue, "")
+	c.SetCondition(ClusterConditionAppDeployed, ConditionStatusTrue, "")
+	c.RemoveCondition(ClusterConditionAppDeployed)
+	assert.Nil(t, c.GetCondition(ClusterConditionAppDeployed))
+	assert.NotNil(t, c.GetCondition(ClusterConditionNodesReady))
+}
+
+func Test_ReadyClusters(t *testing.T) {
+	g := GridConfig{
+		ClusterConfigs: []*ClusterConfig{
+			{Name: "ready", Kubeconfig: "kubeconfig", Status: &ClusterStatus{Phase: ClusterPhaseReady}},
+			{Name: "created-before-status", Kubeconfig: "kubeconfig"},
+			{Name: "failed", Status: &ClusterStatus{Phase: ClusterPhaseFailed}},
+			{Name: "pending", Status: &ClusterStatus{Phase: ClusterPhasePending}},
+			{Name: "no-kubeconfig", Status: &ClusterStatus{Phase: ClusterPhaseReady}},
+		},
+	}
+
+	names := []string{}
+	for _, c := range g.ReadyClusters() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"ready", "created-before-status"}, names)
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	StepCreate     = "create"
+	StepDeploy     = "deploy"
+	StepPreflights = "preflights"
+	StepReadiness  = "readiness"
+	StepChecks     = "checks"
+	StepExperiment = "experiment"
+)
+
+// Steps is the order that steps run in on a cluster
+var Steps = []string{
+	StepCreate,
+	StepDeploy,
+	StepPreflights,
+	StepReadiness,
+	StepChecks,
+	StepExperiment,
+}
+
+// StepResult is the result of a single step on a cluster. The step failed if Error is set
+type StepResult struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func (s StepResult) Duration() time.Duration {
+	return s.FinishedAt.Sub(s.StartedAt)
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
 )
 
@@ -12,6 +13,12 @@ type ValueOrValueFrom struct {
 
 type ValueFrom struct {
 	OSEnv string `json:"osEnv,omitempty"`
+	File  string `json:"file,omitempty"`
+}
+
+// IsEmpty returns true when neither a value nor a source for the value is set
+func (v ValueOrValueFrom) IsEmpty() bool {
+	return v.Value == "" && v.ValueFrom == nil
 }
 
 func (v ValueOrValueFrom) String() (string, error) {
@@ -23,6 +30,13 @@ func (v ValueOrValueFrom) String() (string, error) {
 		if v.ValueFrom.OSEnv != "" {
 			return os.Getenv(v.ValueFrom.OSEnv), nil
 		}
+		if v.ValueFrom.File != "" {
+			b, err := ioutil.ReadFile(v.ValueFrom.File)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
 	}
 
 	return "", errors.New("unable to find supported value")
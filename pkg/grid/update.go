@@ -0,0 +1,34 @@
+package grid
+
+import (
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// Update will replace the grid with the same name in the config file
+func Update(configFilePath string, g *types.GridConfig) error {
+	lockConfig()
+	defer unlockConfig()
+
+	c, err := loadConfig(configFilePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	found := false
+	for i, gridConfig := range c.GridConfigs {
+		if gridConfig.Name == g.Name {
+			c.GridConfigs[i] = g
+			found = true
+		}
+	}
+	if !found {
+		return errors.Errorf("grid %s not found", g.Name)
+	}
+
+	if err := saveConfig(c, configFilePath); err != nil {
+		return errors.Wrap(err, "failed to save config")
+	}
+
+	return nil
+}
@@ -2,38 +2,21 @@ package kubectl
 
 import (
 	"bytes"
-	"io/ioutil"
-	"os"
-	"os/exec"
+	"context"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 )
 
-func Apply(c *types.ClusterConfig, yamlDoc string) error {
-	kubeconfigFile, err := ioutil.TempFile("", "kubectl")
-	if err != nil {
-		return errors.Wrap(err, "failed to create temp file")
-	}
-	defer os.RemoveAll(kubeconfigFile.Name())
+func Apply(ctx context.Context, c *types.ClusterConfig, yamlDoc string) error {
+	return withKubeconfig(c, func(kubeconfigPath string) error {
+		cmd := kubectlCommand(ctx, kubeconfigPath, "apply", "-f", "-")
+		cmd.Stdin = bytes.NewReader([]byte(yamlDoc))
 
-	if err := ioutil.WriteFile(kubeconfigFile.Name(), []byte(c.Kubeconfig), 0644); err != nil {
-		return errors.Wrap(err, "failed to create kubeconfig")
-	}
+		if err := run(cmd); err != nil {
+			return errors.Wrap(err, "failed to run kubectl command")
+		}
 
-	args := []string{
-		"--kubeconfig", kubeconfigFile.Name(),
-		"apply",
-		"-f", "-",
-	}
-
-	cmd := exec.Command("kubectl", args...)
-	cmd.Stdin = bytes.NewReader([]byte(yamlDoc))
-
-	err = run(cmd)
-	if err != nil {
-		return errors.Wrap(err, "failed to run kubectl command")
-	}
-
-	return nil
+		return nil
+	})
 }
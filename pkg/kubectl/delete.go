@@ -0,0 +1,20 @@
+package kubectl
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+func DeleteNamespace(ctx context.Context, c *types.ClusterConfig, namespace string) error {
+	err := runWithKubeconfig(ctx, c,
+		"delete", "namespace", namespace,
+		"--ignore-not-found",
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to run kubectl command")
+	}
+
+	return nil
+}
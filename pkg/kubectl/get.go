@@ -0,0 +1,42 @@
+package kubectl
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// GetJSON will return the resource (for example job/name) as json
+func GetJSON(ctx context.Context, c *types.ClusterConfig, namespace string, resource string) ([]byte, error) {
+	return outputWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"get", resource,
+		"-o", "json",
+	)
+}
+
+// ListJSON will return the resources of the kind that match the label selector as json
+func ListJSON(ctx context.Context, c *types.ClusterConfig, namespace string, kind string, selector string) ([]byte, error) {
+	return outputWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"get", kind,
+		"--selector", selector,
+		"-o", "json",
+	)
+}
+
+// DeleteResource will delete the resource (for example job/name), and will not
+// return an error if it does not exist
+func DeleteResource(ctx context.Context, c *types.ClusterConfig, namespace string, resource string) error {
+	err := runWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"delete", resource,
+		"--ignore-not-found",
+		"--wait",
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete resource")
+	}
+
+	return nil
+}
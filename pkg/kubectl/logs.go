@@ -0,0 +1,35 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// Logs will write the logs of the resource (for example job/name) to w. When follow
+// is true, it will wait for the pod to start and not return until the container exits
+func Logs(ctx context.Context, c *types.ClusterConfig, namespace string, resource string, follow bool, w io.Writer) error {
+	args := []string{
+		"--namespace", namespace,
+		"logs", resource,
+	}
+	if follow {
+		args = append(args, "--follow", "--pod-running-timeout=5m")
+	}
+
+	return withKubeconfig(c, func(kubeconfigPath string) error {
+		cmd := kubectlCommand(ctx, kubeconfigPath, args...)
+		var stderr bytes.Buffer
+		cmd.Stdout = w
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "failed to run kubectl logs: %s", stderr.String())
+		}
+
+		return nil
+	})
+}
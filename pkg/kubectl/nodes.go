@@ -1,17 +1,17 @@
 package kubectl
 
 import (
+	"context"
 	"encoding/json"
-	"io/ioutil"
-	"os"
-	"os/exec"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 )
 
 type Metadata struct {
-	Name string `json:"name"`
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type Conditions struct {
@@ -23,9 +23,15 @@ type Conditions struct {
 
 type Node struct {
 	Metadata Metadata   `json:"metadata"`
+	Spec     NodeSpec   `json:"spec"`
 	Status   NodeStatus `json:"status"`
 }
 
+type NodeSpec struct {
+	ProviderID    string `json:"providerID"`
+	Unschedulable bool   `json:"unschedulable"`
+}
+
 type NodeStatus struct {
 	Conditions []Conditions `json:"conditions"`
 }
@@ -34,35 +40,60 @@ type Nodes struct {
 	Items []Node `json:"items"`
 }
 
-func GetNodes(c *types.ClusterConfig) (Nodes, error) {
-	kubeconfigFile, err := ioutil.TempFile("", "kubectl")
+func GetNodes(ctx context.Context, c *types.ClusterConfig) (Nodes, error) {
+	stdout, err := outputWithKubeconfig(ctx, c,
+		"get", "nodes",
+		"-o", "json",
+	)
 	if err != nil {
-		return Nodes{}, errors.Wrap(err, "failed to create temp file")
+		return Nodes{}, err
 	}
-	defer os.RemoveAll(kubeconfigFile.Name())
 
-	if err := ioutil.WriteFile(kubeconfigFile.Name(), []byte(c.Kubeconfig), 0644); err != nil {
-		return Nodes{}, errors.Wrap(err, "failed to create kubeconfig")
+	nodes := Nodes{}
+	err = json.Unmarshal(stdout, &nodes)
+	if err != nil {
+		return Nodes{}, errors.Wrap(err, "failed to unmarshal nodes")
 	}
 
-	args := []string{
-		"--kubeconfig", kubeconfigFile.Name(),
-		"get", "nodes",
-		"-o", "json",
+	return nodes, nil
+}
+
+// IsReady returns true if the node has a true Ready condition and is schedulable
+func (n Node) IsReady() bool {
+	if n.Spec.Unschedulable {
+		return false
+	}
+
+	for _, condition := range n.Status.Conditions {
+		if condition.Type == "Ready" {
+			return condition.Status == "True"
+		}
 	}
 
-	cmd := exec.Command("kubectl", args...)
+	return false
+}
 
-	stdout, stderr, err := runWithOutput(cmd)
+// DrainNode will cordon the node and evict all pods from it. --delete-emptydir-data
+// was added in kubectl 1.20, older versions only have --delete-local-data
+func DrainNode(ctx context.Context, c *types.ClusterConfig, name string, timeout time.Duration) error {
+	err := runWithKubeconfig(ctx, c,
+		"drain", name,
+		"--ignore-daemonsets",
+		"--delete-emptydir-data",
+		"--force",
+		"--timeout", timeout.String(),
+	)
 	if err != nil {
-		return Nodes{}, errors.Wrapf(err, "failed to run kubectl command: %s", stderr)
+		return errors.Wrap(err, "failed to drain node")
 	}
 
-	nodes := Nodes{}
-	err = json.Unmarshal(stdout, &nodes)
-	if err != nil {
-		return Nodes{}, errors.Wrap(err, "failed to unmarshal nodes")
+	return nil
+}
+
+func UncordonNode(ctx context.Context, c *types.ClusterConfig, name string) error {
+	if err := runWithKubeconfig(ctx, c, "uncordon", name); err != nil {
+		return errors.Wrap(err, "failed to uncordon node")
 	}
 
-	return nodes, nil
+	return nil
 }
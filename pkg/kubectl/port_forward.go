@@ -0,0 +1,29 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// PortForward will forward the local port to the remote port on the target (for example svc/kotsadm)
+// and will not return until the port forward exits
+func PortForward(ctx context.Context, c *types.ClusterConfig, namespace string, target string, localPort int, remotePort int) error {
+	return withKubeconfig(c, func(kubeconfigPath string) error {
+		cmd := kubectlCommand(ctx, kubeconfigPath,
+			"--namespace", namespace,
+			"port-forward", target,
+			fmt.Sprintf("%d:%d", localPort, remotePort),
+		)
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return errors.Wrap(err, "failed to run kubectl port-forward")
+		}
+
+		return nil
+	})
+}
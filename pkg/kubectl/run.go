@@ -1,12 +1,57 @@
 package kubectl
 
 import (
+	"context"
 	"io/ioutil"
+	"os"
 	"os/exec"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 )
 
+// runWithKubeconfig will run kubectl with the args against the cluster
+func runWithKubeconfig(ctx context.Context, c *types.ClusterConfig, args ...string) error {
+	return withKubeconfig(c, func(kubeconfigPath string) error {
+		return run(kubectlCommand(ctx, kubeconfigPath, args...))
+	})
+}
+
+// outputWithKubeconfig will run kubectl with the args against the cluster and return stdout
+func outputWithKubeconfig(ctx context.Context, c *types.ClusterConfig, args ...string) ([]byte, error) {
+	var stdout []byte
+	err := withKubeconfig(c, func(kubeconfigPath string) error {
+		out, stderr, err := runWithOutput(kubectlCommand(ctx, kubeconfigPath, args...))
+		if err != nil {
+			return errors.Wrapf(err, "failed to run kubectl command: %s", stderr)
+		}
+		stdout = out
+		return nil
+	})
+
+	return stdout, err
+}
+
+// withKubeconfig will write the cluster's kubeconfig to a temp file, which is removed after fn returns
+func withKubeconfig(c *types.ClusterConfig, fn func(kubeconfigPath string) error) error {
+	kubeconfigFile, err := ioutil.TempFile("", "kubectl")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.RemoveAll(kubeconfigFile.Name())
+
+	if err := ioutil.WriteFile(kubeconfigFile.Name(), []byte(c.Kubeconfig), 0644); err != nil {
+		return errors.Wrap(err, "failed to create kubeconfig")
+	}
+
+	return fn(kubeconfigFile.Name())
+}
+
+func kubectlCommand(ctx context.Context, kubeconfigPath string, args ...string) *exec.Cmd {
+	allArgs := append([]string{"--kubeconfig", kubeconfigPath}, args...)
+	return exec.CommandContext(ctx, "kubectl", allArgs...)
+}
+
 func run(cmd *exec.Cmd) error {
 	stdout, stderr, err := runWithOutput(cmd)
 	if err != nil {
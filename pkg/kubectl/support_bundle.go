@@ -0,0 +1,30 @@
+package kubectl
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// SupportBundle will collect a support bundle from the cluster using the support-bundle
+// kubectl plugin, and write it to outputPath
+func SupportBundle(ctx context.Context, c *types.ClusterConfig, specPath string, outputPath string) error {
+	return withKubeconfig(c, func(kubeconfigPath string) error {
+		// flags for a plugin have to be after its name, so this can't use kubectlCommand
+		cmd := exec.CommandContext(ctx, "kubectl",
+			"support-bundle",
+			"--kubeconfig", kubeconfigPath,
+			"--interactive=false",
+			"--output", outputPath,
+			specPath,
+		)
+
+		if err := run(cmd); err != nil {
+			return errors.Wrap(err, "failed to run kubectl support-bundle")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+// DeletePods will delete all pods in the namespace that match the label selector
+func DeletePods(ctx context.Context, c *types.ClusterConfig, namespace string, selector string) error {
+	err := runWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"delete", "pods",
+		"--selector", selector,
+		"--wait=false",
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete pods")
+	}
+
+	return nil
+}
+
+func ScaleDeployment(ctx context.Context, c *types.ClusterConfig, namespace string, name string, replicas int) error {
+	err := runWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"scale", fmt.Sprintf("deployment/%s", name),
+		"--replicas", strconv.Itoa(replicas),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to scale deployment")
+	}
+
+	return nil
+}
+
+// WaitForRollout will wait until the rollout of the resource has completed
+func WaitForRollout(ctx context.Context, c *types.ClusterConfig, namespace string, resource string, timeout time.Duration) error {
+	err := runWithKubeconfig(ctx, c,
+		"--namespace", namespace,
+		"rollout", "status", resource,
+		"--timeout", timeout.String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to wait for rollout")
+	}
+
+	return nil
+}
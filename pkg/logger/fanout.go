@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+)
+
+// FanOutLogger sends every message to each of its loggers, such as the terminal, Slack and a webhook
+type FanOutLogger struct {
+	loggers []Logger
+}
+
+func NewFanOutLogger(loggers ...Logger) Logger {
+	return &FanOutLogger{
+		loggers: loggers,
+	}
+}
+
+func (l *FanOutLogger) WithFields(fields Fields) Logger {
+	children := []Logger{}
+	for _, logger := range l.loggers {
+		children = append(children, logger.WithFields(fields))
+	}
+	return NewFanOutLogger(children...)
+}
+
+func (l *FanOutLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+func (l *FanOutLogger) Silence() {
+	for _, logger := range l.loggers {
+		logger.Silence()
+	}
+}
+
+func (l *FanOutLogger) Verbose() {
+	for _, logger := range l.loggers {
+		logger.Verbose()
+	}
+}
+
+func (l *FanOutLogger) Initialize() {
+	for _, logger := range l.loggers {
+		logger.Initialize()
+	}
+}
+
+func (l *FanOutLogger) Finish() {
+	for _, logger := range l.loggers {
+		logger.Finish()
+	}
+}
+
+func (l *FanOutLogger) Debug(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Debug(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) Info(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.Info(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.ActionWithoutSpinner(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.ChildActionWithoutSpinner(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) ActionWithSpinner(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.ActionWithSpinner(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) ChildActionWithSpinner(msg string, args ...interface{}) {
+	for _, logger := range l.loggers {
+		logger.ChildActionWithSpinner(msg, args...)
+	}
+}
+
+func (l *FanOutLogger) FinishChildSpinner() {
+	for _, logger := range l.loggers {
+		logger.FinishChildSpinner()
+	}
+}
+
+func (l *FanOutLogger) FinishSpinner() {
+	for _, logger := range l.loggers {
+		logger.FinishSpinner()
+	}
+}
+
+func (l *FanOutLogger) FinishSpinnerWithError() {
+	for _, logger := range l.loggers {
+		logger.FinishSpinnerWithError()
+	}
+}
+
+func (l *FanOutLogger) Error(err error) {
+	for _, logger := range l.loggers {
+		logger.Error(err)
+	}
+}
+
+// TaskQueued, TaskStarted and TaskFinished notify each logger that observes tasks
+func (l *FanOutLogger) TaskQueued(cluster string) {
+	for _, logger := range l.loggers {
+		if observer, ok := logger.(orchestrator.Observer); ok {
+			observer.TaskQueued(cluster)
+		}
+	}
+}
+
+func (l *FanOutLogger) TaskStarted(cluster string) {
+	for _, logger := range l.loggers {
+		if observer, ok := logger.(orchestrator.Observer); ok {
+			observer.TaskStarted(cluster)
+		}
+	}
+}
+
+func (l *FanOutLogger) TaskFinished(cluster string, err error) {
+	for _, logger := range l.loggers {
+		if observer, ok := logger.(orchestrator.Observer); ok {
+			observer.TaskFinished(cluster, err)
+		}
+	}
+}
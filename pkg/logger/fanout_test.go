@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FanOutLogger(t *testing.T) {
+	var first, second bytes.Buffer
+	l := NewFanOutLogger(NewJSONLogger(&first), NewJSONLogger(&second))
+
+	l.WithCluster("my-cluster").Info("Creating VPC")
+
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 1)
+		assert.Contains(t, lines[0], `"cluster":"my-cluster"`)
+		assert.Contains(t, lines[0], `"message":"Creating VPC"`)
+	}
+}
@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelError = "error"
+)
+
+const (
+	// EventStart is the event when a spinner is started
+	EventStart = "start"
+	// EventFinish is the event when a spinner is finished, with the duration since the start
+	EventFinish = "finish"
+)
+
+// JSONEvent is written as a single line for each message
+type JSONEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Grid      string    `json:"grid,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Step      string    `json:"step,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	// Event and DurationSeconds are set for spinners, which are written as a start and a finish event
+	Event           string            `json:"event,omitempty"`
+	DurationSeconds *float64          `json:"durationSeconds,omitempty"`
+	Fields          map[string]string `json:"fields,omitempty"`
+}
+
+// JSONLogger writes every message as a JSON object on its own line, so that the logs can be
+// parsed in CI
+type JSONLogger struct {
+	// mu is shared with child loggers, so that lines from different clusters are not interleaved
+	mu  *sync.Mutex
+	out io.Writer
+	now func() time.Time
+
+	spinner      *jsonSpinner
+	childSpinner *jsonSpinner
+
+	isSilent  bool
+	isVerbose bool
+	fields    Fields
+}
+
+type jsonSpinner struct {
+	message   string
+	startedAt time.Time
+}
+
+func NewJSONLogger(out io.Writer) Logger {
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return &JSONLogger{
+		mu:  &sync.Mutex{},
+		out: out,
+		now: time.Now,
+	}
+}
+
+func (l *JSONLogger) WithFields(fields Fields) Logger {
+	return &JSONLogger{
+		mu:        l.mu,
+		out:       l.out,
+		now:       l.now,
+		isSilent:  l.isSilent,
+		isVerbose: l.isVerbose,
+		fields:    l.fields.with(fields),
+	}
+}
+
+func (l *JSONLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+func (l *JSONLogger) Silence() {
+	if l == nil {
+		return
+	}
+	l.isSilent = true
+}
+
+func (l *JSONLogger) Verbose() {
+	if l == nil {
+		return
+	}
+	l.isVerbose = true
+}
+
+func (l *JSONLogger) Initialize() {
+}
+
+func (l *JSONLogger) Finish() {
+}
+
+func (l *JSONLogger) Debug(msg string, args ...interface{}) {
+	if l == nil || l.isSilent || !l.isVerbose {
+		return
+	}
+
+	l.write(l.newEvent(LevelDebug, fmt.Sprintf(msg, args...)))
+}
+
+func (l *JSONLogger) Info(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.write(l.newEvent(LevelInfo, fmt.Sprintf(msg, args...)))
+}
+
+func (l *JSONLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent || msg == "" {
+		return
+	}
+
+	l.write(l.newEvent(LevelInfo, fmt.Sprintf(msg, args...)))
+}
+
+func (l *JSONLogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
+	l.ActionWithoutSpinner(msg, args...)
+}
+
+func (l *JSONLogger) ActionWithSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.spinner = l.startSpinner(fmt.Sprintf(msg, args...))
+}
+
+func (l *JSONLogger) ChildActionWithSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.childSpinner = l.startSpinner(fmt.Sprintf(msg, args...))
+}
+
+func (l *JSONLogger) FinishChildSpinner() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.childSpinner, LevelInfo)
+	l.childSpinner = nil
+}
+
+func (l *JSONLogger) FinishSpinner() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.spinner, LevelInfo)
+	l.spinner = nil
+}
+
+func (l *JSONLogger) FinishSpinnerWithError() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.spinner, LevelError)
+	l.spinner = nil
+}
+
+func (l *JSONLogger) Error(err error) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	event := l.newEvent(LevelError, "")
+	event.Error = err.Error()
+	l.write(event)
+}
+
+func (l *JSONLogger) startSpinner(message string) *jsonSpinner {
+	s := &jsonSpinner{
+		message:   message,
+		startedAt: l.now(),
+	}
+
+	event := l.newEvent(LevelInfo, message)
+	event.Event = EventStart
+	l.write(event)
+
+	return s
+}
+
+// finishSpinner writes the finish event, with the message from the start event
+func (l *JSONLogger) finishSpinner(s *jsonSpinner, level string) {
+	if s == nil {
+		return
+	}
+
+	event := l.newEvent(level, s.message)
+	event.Event = EventFinish
+	duration := event.Timestamp.Sub(s.startedAt).Seconds()
+	event.DurationSeconds = &duration
+	l.write(event)
+}
+
+func (l *JSONLogger) newEvent(level string, message string) JSONEvent {
+	return newJSONEvent(l.now(), l.fields, level, message)
+}
+
+// newJSONEvent returns an event with the fields of a logger
+func newJSONEvent(timestamp time.Time, fields Fields, level string, message string) JSONEvent {
+	event := JSONEvent{
+		Timestamp: timestamp,
+		Level:     level,
+		Message:   message,
+	}
+
+	for k, v := range fields {
+		switch k {
+		case FieldGrid:
+			event.Grid = v
+		case FieldCluster:
+			event.Cluster = v
+		case FieldStep:
+			event.Step = v
+		default:
+			if event.Fields == nil {
+				event.Fields = map[string]string{}
+			}
+			event.Fields[k] = v
+		}
+	}
+
+	return event
+}
+
+func (l *JSONLogger) write(event JSONEvent) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		log.Println("failed to marshal log event", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintln(l.out, string(b))
+}
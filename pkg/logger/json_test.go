@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_JSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf).(*JSONLogger)
+
+	now := time.Date(2021, 1, 15, 9, 30, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	log := l.WithFields(Fields{FieldGrid: "my-grid", FieldCluster: "my-cluster", FieldStep: "vpc", "region": "us-east-1"})
+	log.Info("Creating VPC for %s", "my-cluster")
+	log.ActionWithSpinner("Waiting for control plane")
+	now = now.Add(90 * time.Second)
+	log.FinishSpinnerWithError()
+	log.Error(errors.New("timed out"))
+	log.Debug("not verbose")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+
+	events := []JSONEvent{}
+	for _, line := range lines {
+		event := JSONEvent{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+
+	assert.Equal(t, LevelInfo, events[0].Level)
+	assert.Equal(t, "my-grid", events[0].Grid)
+	assert.Equal(t, "my-cluster", events[0].Cluster)
+	assert.Equal(t, "vpc", events[0].Step)
+	assert.Equal(t, "Creating VPC for my-cluster", events[0].Message)
+	assert.Equal(t, map[string]string{"region": "us-east-1"}, events[0].Fields)
+	assert.Nil(t, events[0].DurationSeconds)
+
+	// spinners are a start and a finish event, with the same message
+	assert.Equal(t, EventStart, events[1].Event)
+	assert.Equal(t, "Waiting for control plane", events[1].Message)
+	assert.Equal(t, EventFinish, events[2].Event)
+	assert.Equal(t, LevelError, events[2].Level)
+	assert.Equal(t, "Waiting for control plane", events[2].Message)
+	require.NotNil(t, events[2].DurationSeconds)
+	assert.Equal(t, float64(90), *events[2].DurationSeconds)
+
+	assert.Equal(t, LevelError, events[3].Level)
+	assert.Equal(t, "timed out", events[3].Error)
+}
@@ -1,7 +1,17 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
 )
 
 type Logger interface {
@@ -19,11 +29,129 @@ type Logger interface {
 	FinishSpinner()
 	FinishSpinnerWithError()
 	Error(err error)
+	// WithFields returns a child logger that adds the fields to every message
+	WithFields(fields Fields) Logger
+	// WithCluster returns a child logger that adds the cluster name to every message
+	WithCluster(cluster string) Logger
 }
 
+// NewLogger returns a logger that writes to the terminal, as text or JSON, and also sends every
+// message to Slack and to each webhook in loggerSpec
 func NewLogger(loggerSpec types.LoggerSpec) Logger {
+	console := NewTerminalLogger()
+	if loggerSpec.JSON != nil {
+		console = NewJSONLogger(os.Stdout)
+	}
+
+	notifiers := newNotificationLoggers(loggerSpec)
+	if len(notifiers) == 0 {
+		return console
+	}
+	return NewFanOutLogger(append([]Logger{console}, notifiers...)...)
+}
+
+// newNotificationLoggers returns the loggers in loggerSpec that don't write to the terminal
+func newNotificationLoggers(loggerSpec types.LoggerSpec) []Logger {
+	loggers := []Logger{}
 	if loggerSpec.Slack != nil {
-		return NewSlackLogger(loggerSpec.Slack)
+		loggers = append(loggers, NewSlackLogger(loggerSpec.Slack))
+	}
+	for _, webhook := range loggerSpec.Webhooks {
+		loggers = append(loggers, NewWebhookLogger(webhook))
+	}
+	return loggers
+}
+
+// NewOperationLogger returns the logger for an operation on a grid, such as a create or a deploy,
+// with the fields that describe the operation. The logger is notified of the tasks run with the
+// returned context, and Finish must be called when the operation is complete. When the progress
+// is being watched, messages are shown in the watch table instead of written to the terminal
+func NewOperationLogger(ctx context.Context, loggerSpec types.LoggerSpec, fields Fields) (context.Context, Logger) {
+	var log Logger
+	if t := watch.FromContext(ctx); t != nil {
+		watchLog := &WatchLogger{
+			table: t,
+		}
+		if notifiers := newNotificationLoggers(loggerSpec); len(notifiers) > 0 {
+			watchLog.next = NewFanOutLogger(notifiers...)
+		}
+		log = watchLog
+	} else {
+		log = NewLogger(loggerSpec)
+	}
+
+	log = log.WithFields(fields)
+	if observer, ok := log.(orchestrator.Observer); ok {
+		ctx = orchestrator.WithObserver(ctx, observer)
 	}
-	return NewTerminalLogger()
+
+	log.Initialize()
+	return ctx, log
+}
+
+const (
+	// FieldCluster is the field that WithCluster sets
+	FieldCluster = "cluster"
+	FieldGrid    = "grid"
+	FieldStep    = "step"
+	// FieldOperation, FieldApp and FieldChannel describe the operation that an operation logger is for
+	FieldOperation = "operation"
+	FieldApp       = "app"
+	FieldChannel   = "channel"
+)
+
+// Fields are added to every message from a child logger
+type Fields map[string]string
+
+// with returns a copy of f with fields added
+func (f Fields) with(fields Fields) Fields {
+	merged := Fields{}
+	for k, v := range f {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// String formats the fields as the cluster name, followed by the other fields sorted by key
+func (f Fields) String() string {
+	parts := []string{}
+	if cluster, ok := f[FieldCluster]; ok {
+		parts = append(parts, cluster)
+	}
+
+	keys := []string{}
+	for k := range f {
+		if k != FieldCluster {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, f[k]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+var clusterColors = []color.Attribute{
+	color.FgCyan,
+	color.FgMagenta,
+	color.FgYellow,
+	color.FgBlue,
+	color.FgGreen,
+	color.FgHiCyan,
+	color.FgHiMagenta,
+	color.FgHiYellow,
+	color.FgHiBlue,
+}
+
+// clusterColor returns the same color for a cluster every time, so that the lines from each
+// cluster can be followed when several clusters are logging at once
+func clusterColor(cluster string) *color.Color {
+	h := fnv.New32a()
+	h.Write([]byte(cluster))
+	return color.New(clusterColors[h.Sum32()%uint32(len(clusterColors))])
 }
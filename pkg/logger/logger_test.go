@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FieldsString(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Fields
+		want   string
+	}{
+		{
+			name:   "cluster only",
+			fields: Fields{FieldCluster: "my-cluster"},
+			want:   "my-cluster",
+		},
+		{
+			name:   "cluster first, then sorted fields",
+			fields: Fields{"step": "deploy", FieldCluster: "my-cluster", "app": "my-app"},
+			want:   "my-cluster app=my-app step=deploy",
+		},
+		{
+			name:   "no cluster",
+			fields: Fields{"app": "my-app"},
+			want:   "app=my-app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.fields.String())
+		})
+	}
+}
+
+func Test_WithFields(t *testing.T) {
+	parent := NewTerminalLogger().WithCluster("my-cluster").(*TerminalLogger)
+	child := parent.WithFields(Fields{"app": "my-app"}).(*TerminalLogger)
+
+	// the parent's fields are not changed by the child
+	assert.Equal(t, Fields{FieldCluster: "my-cluster"}, parent.fields)
+	assert.Equal(t, Fields{FieldCluster: "my-cluster", "app": "my-app"}, child.fields)
+}
@@ -3,30 +3,81 @@ package logger
 import (
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
 	"github.com/slack-go/slack"
 )
 
+const (
+	slackStatusPending   = "pending"
+	slackStatusRunning   = "running"
+	slackStatusSucceeded = "succeeded"
+	slackStatusFailed    = "failed"
+)
+
+// slackMaxSectionLength is the most text that slack accepts in a section block
+const slackMaxSectionLength = 3000
+
+var slackStatusEmoji = map[string]string{
+	slackStatusPending:   ":hourglass_flowing_sand:",
+	slackStatusRunning:   ":arrows_counterclockwise:",
+	slackStatusSucceeded: ":white_check_mark:",
+	slackStatusFailed:    ":x:",
+}
+
 type SlackLogger struct {
 	// slack auth
 	token   string
 	channel string
 	client  *slack.Client
 
-	// slack message state
-	threadTS  string
-	channelID string // why?
+	// slack message state, shared with child loggers
+	thread *slackThread
 
 	// general logger stuff
 	isSilent  bool
 	isVerbose bool
+	fields    Fields
+}
+
+// slackThread is the parent message for an operation, with a summary of the status of each
+// cluster that is updated in place. Messages from each cluster are replies in the thread
+type slackThread struct {
+	// mu guards the status of the operation, and is not held while calling slack
+	mu                 sync.Mutex
+	startedAt          time.Time
+	finishedAt         time.Time
+	failureUserGroupID string
+	clusters           []*slackClusterStatus
+
+	// sendMu is held while calling slack, so that the messages are sent in order and the
+	// summary that is sent last is the latest one
+	sendMu    sync.Mutex
+	channelID string
+	threadTS  string
+}
+
+type slackClusterStatus struct {
+	name   string
+	status string
+	step   string
 }
 
 func NewSlackLogger(loggerSpec *types.SlackLoggerSpec) Logger {
-	l := &SlackLogger{}
+	return newSlackLogger(loggerSpec)
+}
+
+// newSlackLogger accepts options for the slack client, so that it can use a local server in tests
+func newSlackLogger(loggerSpec *types.SlackLoggerSpec, options ...slack.Option) *SlackLogger {
+	l := &SlackLogger{
+		thread: &slackThread{
+			failureUserGroupID: loggerSpec.FailureUserGroupID,
+		},
+	}
 
 	token, err := loggerSpec.Token.String()
 	if err != nil {
@@ -45,16 +96,39 @@ func NewSlackLogger(loggerSpec *types.SlackLoggerSpec) Logger {
 	}
 
 	if !l.isSilent {
-		l.client = slack.New(l.token)
+		l.client = slack.New(l.token, options...)
+	}
 
-		err = l.startMessageThread()
-		if err != nil {
-			l.isSilent = true
-			log.Println("failed to create initial slack message", err)
+	return l
+}
+
+// WithFields returns a logger that replies in the same thread, with the fields as a tag on each message
+func (l *SlackLogger) WithFields(fields Fields) Logger {
+	child := *l
+	child.fields = l.fields.with(fields)
+	return &child
+}
+
+func (l *SlackLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+// tag is added to the start of every reply. The fields that describe the operation are only
+// in the parent message
+func (l *SlackLogger) tag() string {
+	fields := Fields{}
+	for k, v := range l.fields {
+		switch k {
+		case FieldGrid, FieldOperation, FieldApp, FieldChannel:
+		default:
+			fields[k] = v
 		}
 	}
+	if len(fields) == 0 {
+		return ""
+	}
 
-	return l
+	return fmt.Sprintf("`%s` ", fields.String())
 }
 
 func (l *SlackLogger) Silence() {
@@ -71,10 +145,52 @@ func (l *SlackLogger) Verbose() {
 	l.isVerbose = true
 }
 
+// Initialize posts the parent message for the operation
 func (l *SlackLogger) Initialize() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.thread.sendMu.Lock()
+	defer l.thread.sendMu.Unlock()
+
+	if err := l.startMessageThread(); err != nil {
+		log.Println("failed to create initial slack message", err)
+	}
 }
 
+// Finish updates the summary a final time and replies with whether the operation passed,
+// mentioning the failure user group when any cluster failed
 func (l *SlackLogger) Finish() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.thread.mu.Lock()
+	l.thread.finishedAt = time.Now()
+	failed := []string{}
+	for _, c := range l.thread.clusters {
+		if c.status == slackStatusFailed {
+			failed = append(failed, c.name)
+		}
+	}
+
+	var text string
+	if len(failed) == 0 {
+		text = fmt.Sprintf("%s %s succeeded on %d/%d clusters", slackStatusEmoji[slackStatusSucceeded], l.title(), len(l.thread.clusters), len(l.thread.clusters))
+	} else {
+		text = fmt.Sprintf("%s %s failed on %d/%d clusters: %s", slackStatusEmoji[slackStatusFailed], l.title(), len(failed), len(l.thread.clusters), strings.Join(failed, ", "))
+		if l.thread.failureUserGroupID != "" {
+			text = fmt.Sprintf("%s <!subteam^%s>", text, l.thread.failureUserGroupID)
+		}
+	}
+	l.thread.mu.Unlock()
+
+	l.thread.sendMu.Lock()
+	defer l.thread.sendMu.Unlock()
+
+	l.updateSummary()
+	l.reply(text, slack.MsgOptionBroadcast())
 }
 
 func (l *SlackLogger) Debug(msg string, args ...interface{}) {
@@ -82,9 +198,7 @@ func (l *SlackLogger) Debug(msg string, args ...interface{}) {
 		return
 	}
 
-	fmt.Printf("    ")
-	fmt.Println(fmt.Sprintf(msg, args...))
-	fmt.Println("")
+	l.Info(msg, args...)
 }
 
 func (l *SlackLogger) Info(msg string, args ...interface{}) {
@@ -92,38 +206,37 @@ func (l *SlackLogger) Info(msg string, args ...interface{}) {
 		return
 	}
 
-	_, _, err := l.client.PostMessage(
-		l.channel,
-		slack.MsgOptionText(fmt.Sprintf(msg, args...), false), // TODO: needs app slug, release sequense
-		slack.MsgOptionTS(l.threadTS),
-		slack.MsgOptionAsUser(true),
-	)
-	if err != nil {
-		log.Println("failed to send slack message", err)
+	// the summary shows the step that each cluster is on
+	l.thread.mu.Lock()
+	cluster, step := l.fields[FieldCluster], l.fields[FieldStep]
+	stepChanged := false
+	if c := l.thread.cluster(cluster); c != nil && step != "" && c.step != step {
+		c.step = step
+		stepChanged = true
 	}
-}
+	l.thread.mu.Unlock()
 
-func (l *SlackLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
-		return
+	l.thread.sendMu.Lock()
+	defer l.thread.sendMu.Unlock()
+
+	if stepChanged {
+		l.updateSummary()
 	}
+	l.reply(l.tag() + fmt.Sprintf(msg, args...))
+}
 
-	if msg == "" {
-		fmt.Println("")
+// ActionWithoutSpinner and ChildActionWithoutSpinner reply in the thread, the terminal logger
+// writes them to the terminal
+func (l *SlackLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent || msg == "" {
 		return
 	}
 
-	fmt.Printf("  • ")
-	fmt.Println(fmt.Sprintf(msg, args...))
+	l.Info(msg, args...)
 }
 
 func (l *SlackLogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
-		return
-	}
-
-	fmt.Printf("    • ")
-	fmt.Println(fmt.Sprintf(msg, args...))
+	l.ActionWithoutSpinner(msg, args...)
 }
 
 func (l *SlackLogger) ActionWithSpinner(msg string, args ...interface{}) {
@@ -254,34 +367,206 @@ func (l *SlackLogger) Error(err error) {
 		return
 	}
 
-	c := color.New(color.FgHiRed)
-	c.Printf("  • ")
-	c.Println(fmt.Sprintf("%#v", err))
+	l.thread.sendMu.Lock()
+	defer l.thread.sendMu.Unlock()
+
+	l.reply(fmt.Sprintf("%s %s%s", slackStatusEmoji[slackStatusFailed], l.tag(), err.Error()))
+}
+
+func (l *SlackLogger) TaskQueued(cluster string) {
+	l.setClusterStatus(cluster, slackStatusPending)
+}
+
+func (l *SlackLogger) TaskStarted(cluster string) {
+	l.setClusterStatus(cluster, slackStatusRunning)
 }
 
+func (l *SlackLogger) TaskFinished(cluster string, err error) {
+	if err != nil {
+		l.setClusterStatus(cluster, slackStatusFailed)
+		return
+	}
+	l.setClusterStatus(cluster, slackStatusSucceeded)
+}
+
+func (l *SlackLogger) setClusterStatus(cluster string, status string) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.thread.mu.Lock()
+	c := l.thread.cluster(cluster)
+	if c == nil {
+		c = &slackClusterStatus{
+			name: cluster,
+		}
+		l.thread.clusters = append(l.thread.clusters, c)
+	}
+	c.status = status
+	l.thread.mu.Unlock()
+
+	l.thread.sendMu.Lock()
+	defer l.thread.sendMu.Unlock()
+
+	l.updateSummary()
+}
+
+// cluster returns the status of the cluster in the summary, or nil when it's not in the summary.
+// The caller must hold the thread lock
+func (t *slackThread) cluster(name string) *slackClusterStatus {
+	for _, c := range t.clusters {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// startMessageThread posts the parent message, if it hasn't been posted yet. The caller must hold the send lock
 func (l *SlackLogger) startMessageThread() error {
+	if l.thread.threadTS != "" {
+		return nil
+	}
+
 	channelID, timestamp, err := l.client.PostMessage(
 		l.channel,
-		slack.MsgOptionText("Starting grid test", false), // TODO: needs app slug, release sequense
+		slack.MsgOptionText(l.title(), false),
+		slack.MsgOptionBlocks(l.currentSummaryBlocks()...),
 		slack.MsgOptionAsUser(true),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to send slack message")
 	}
 
-	l.threadTS = timestamp
-	l.channelID = channelID
+	l.thread.threadTS = timestamp
+	l.thread.channelID = channelID
+
+	l.thread.mu.Lock()
+	l.thread.startedAt = time.Now()
+	l.thread.mu.Unlock()
 	return nil
 }
 
-// func (l *SlackLogger) callSlack(url string) ([]byte, error) {
-// 	resp, err := http.Get(url)
-// 	defer resp.Body.Close()
+// updateSummary replaces the parent message with the current summary. The caller must hold the send lock
+func (l *SlackLogger) updateSummary() {
+	if l.thread.threadTS == "" {
+		if err := l.startMessageThread(); err != nil {
+			log.Println("failed to create initial slack message", err)
+		}
+		return
+	}
+
+	_, _, _, err := l.client.UpdateMessage(
+		l.thread.channelID,
+		l.thread.threadTS,
+		slack.MsgOptionText(l.title(), false),
+		slack.MsgOptionBlocks(l.currentSummaryBlocks()...),
+	)
+	if err != nil {
+		log.Println("failed to update slack message", err)
+	}
+}
+
+// reply posts text in the thread. The caller must hold the send lock
+func (l *SlackLogger) reply(text string, options ...slack.MsgOption) {
+	if err := l.startMessageThread(); err != nil {
+		log.Println("failed to create initial slack message", err)
+		return
+	}
+
+	options = append([]slack.MsgOption{
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(l.thread.threadTS),
+		slack.MsgOptionAsUser(true),
+	}, options...)
+	if _, _, err := l.client.PostMessage(l.channel, options...); err != nil {
+		log.Println("failed to send slack message", err)
+	}
+}
+
+// title describes the operation, such as "*Deploy* on grid `my-grid`"
+func (l *SlackLogger) title() string {
+	operation := l.fields[FieldOperation]
+	if operation == "" {
+		operation = "Grid test"
+	}
+
+	title := fmt.Sprintf("*%s*", operation)
+	if grid := l.fields[FieldGrid]; grid != "" {
+		title = fmt.Sprintf("%s on grid `%s`", title, grid)
+	}
+	return title
+}
 
-// 	body, err := ioutil.ReadAll(resp.Body)
-// 	if err != nil {
-// 		return nil, err
-// 	}
+// currentSummaryBlocks returns the summary blocks while holding the thread lock
+func (l *SlackLogger) currentSummaryBlocks() []slack.Block {
+	l.thread.mu.Lock()
+	defer l.thread.mu.Unlock()
 
-// 	return body, nil
-// }
+	return l.summaryBlocks()
+}
+
+// summaryBlocks are the blocks of the parent message, with a line for each cluster. The lines are
+// in as few sections as fit, so that large grids stay under slack's limit on the number of blocks.
+// The caller must hold the thread lock
+func (l *SlackLogger) summaryBlocks() []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slackMarkdown(l.title()), nil, nil),
+	}
+
+	details := []string{}
+	if app := l.fields[FieldApp]; app != "" {
+		details = append(details, fmt.Sprintf("App: %s", app))
+	}
+	if channel := l.fields[FieldChannel]; channel != "" {
+		details = append(details, fmt.Sprintf("Channel: %s", channel))
+	}
+	if !l.thread.finishedAt.IsZero() {
+		details = append(details, fmt.Sprintf("Finished in %s", l.thread.finishedAt.Sub(l.thread.startedAt).Round(time.Second)))
+	}
+	if len(details) > 0 {
+		blocks = append(blocks, slack.NewContextBlock("", slackMarkdown(strings.Join(details, " | "))))
+	}
+
+	if len(l.thread.clusters) > 0 {
+		blocks = append(blocks, slack.NewDividerBlock())
+	}
+	lines := []string{}
+	for _, c := range l.thread.clusters {
+		status := fmt.Sprintf("%s %s", slackStatusEmoji[c.status], c.status)
+		if c.step != "" && c.status != slackStatusSucceeded {
+			status = fmt.Sprintf("%s (%s)", status, c.step)
+		}
+		lines = append(lines, fmt.Sprintf("*%s*  %s", c.name, status))
+	}
+	for _, section := range slackSections(lines, slackMaxSectionLength) {
+		blocks = append(blocks, slack.NewSectionBlock(slackMarkdown(section), nil, nil))
+	}
+
+	return blocks
+}
+
+// slackSections joins the lines into as few sections of at most maxLength as possible
+func slackSections(lines []string, maxLength int) []string {
+	sections := []string{}
+	section := ""
+	for _, line := range lines {
+		if section != "" && len(section)+len("\n")+len(line) > maxLength {
+			sections = append(sections, section)
+			section = ""
+		}
+		if section != "" {
+			section += "\n"
+		}
+		section += line
+	}
+	if section != "" {
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+func slackMarkdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slackCall is a request to the local stand-in for the slack api
+type slackCall struct {
+	method string
+	values url.Values
+}
+
+type fakeSlack struct {
+	mu    sync.Mutex
+	calls []slackCall
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, slackCall{
+		method: strings.TrimPrefix(r.URL.Path, "/"),
+		values: r.PostForm,
+	})
+
+	ts := r.PostForm.Get("ts")
+	if ts == "" {
+		ts = fmt.Sprintf("1610700000.%06d", len(f.calls))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":      true,
+		"channel": "C0123",
+		"ts":      ts,
+	})
+}
+
+func Test_SlackLogger(t *testing.T) {
+	fake := &fakeSlack{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	spec := &types.SlackLoggerSpec{
+		Token:              types.ValueOrValueFrom{Value: "xoxb-token"},
+		Channel:            types.ValueOrValueFrom{Value: "#grid"},
+		FailureUserGroupID: "S0123",
+	}
+	l := newSlackLogger(spec, slack.OptionAPIURL(server.URL+"/")).WithFields(Fields{
+		FieldOperation: "Deploy",
+		FieldGrid:      "my-grid",
+		FieldApp:       "my-app",
+	}).(*SlackLogger)
+
+	l.Initialize()
+	l.TaskQueued("a")
+	l.TaskQueued("b")
+	l.TaskStarted("a")
+	l.WithCluster("a").WithFields(Fields{FieldStep: "deploy"}).Info("Installing application %s", "my-app")
+	l.TaskFinished("a", nil)
+	l.TaskStarted("b")
+	l.WithCluster("b").Error(errors.New("timed out"))
+	l.TaskFinished("b", errors.New("timed out"))
+	l.Finish()
+
+	methods := []string{}
+	for _, call := range fake.calls {
+		methods = append(methods, call.method)
+	}
+	assert.Equal(t, []string{
+		"chat.postMessage", // parent
+		"chat.update",      // a pending
+		"chat.update",      // b pending
+		"chat.update",      // a running
+		"chat.update",      // a on the deploy step
+		"chat.postMessage", // a installing
+		"chat.update",      // a succeeded
+		"chat.update",      // b running
+		"chat.postMessage", // b error
+		"chat.update",      // b failed
+		"chat.update",      // finished
+		"chat.postMessage", // summary
+	}, methods)
+
+	parent := fake.calls[0].values
+	assert.Equal(t, "#grid", parent.Get("channel"))
+	assert.Empty(t, parent.Get("thread_ts"))
+	assert.Contains(t, parent.Get("blocks"), "*Deploy* on grid `my-grid`")
+	assert.Contains(t, parent.Get("blocks"), "App: my-app")
+	threadTS := "1610700000.000001"
+
+	// every update replaces the parent message
+	for _, call := range fake.calls {
+		if call.method == "chat.update" {
+			assert.Equal(t, "C0123", call.values.Get("channel"))
+			assert.Equal(t, threadTS, call.values.Get("ts"))
+		}
+	}
+	assert.Contains(t, fake.calls[4].values.Get("blocks"), "running (deploy)")
+	assert.Contains(t, fake.calls[10].values.Get("blocks"), "failed")
+	assert.Contains(t, fake.calls[10].values.Get("blocks"), "Finished in")
+
+	installing := fake.calls[5].values
+	assert.Equal(t, threadTS, installing.Get("thread_ts"))
+	assert.Equal(t, "`a step=deploy` Installing application my-app", installing.Get("text"))
+
+	assert.Equal(t, ":x: `b` timed out", fake.calls[8].values.Get("text"))
+
+	summary := fake.calls[11].values
+	require.Equal(t, threadTS, summary.Get("thread_ts"))
+	assert.Equal(t, "true", summary.Get("reply_broadcast"))
+	assert.Equal(t, ":x: *Deploy* on grid `my-grid` failed on 1/2 clusters: b <!subteam^S0123>", summary.Get("text"))
+}
+
+func Test_SlackLoggerSucceeded(t *testing.T) {
+	fake := &fakeSlack{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	spec := &types.SlackLoggerSpec{
+		Token:              types.ValueOrValueFrom{Value: "xoxb-token"},
+		Channel:            types.ValueOrValueFrom{Value: "#grid"},
+		FailureUserGroupID: "S0123",
+	}
+	l := newSlackLogger(spec, slack.OptionAPIURL(server.URL+"/")).WithFields(Fields{
+		FieldOperation: "Create",
+		FieldGrid:      "my-grid",
+	}).(*SlackLogger)
+
+	l.Initialize()
+	l.TaskQueued("a")
+	l.TaskStarted("a")
+	l.TaskFinished("a", nil)
+	l.Finish()
+
+	summary := fake.calls[len(fake.calls)-1].values
+	assert.Equal(t, ":white_check_mark: *Create* on grid `my-grid` succeeded on 1/1 clusters", summary.Get("text"))
+}
+
+func Test_slackSummaryBlocksManyClusters(t *testing.T) {
+	l := newSlackLogger(&types.SlackLoggerSpec{}).WithFields(Fields{
+		FieldOperation: "Deploy",
+		FieldGrid:      "my-grid",
+	}).(*SlackLogger)
+	for i := 0; i < 500; i++ {
+		l.thread.clusters = append(l.thread.clusters, &slackClusterStatus{
+			name:   fmt.Sprintf("cluster-%03d", i),
+			status: slackStatusRunning,
+			step:   "deploy",
+		})
+	}
+
+	// slack rejects messages with more than 50 blocks, or sections with more than 3000 characters
+	blocks := l.summaryBlocks()
+	assert.True(t, len(blocks) <= 50, "%d blocks", len(blocks))
+
+	clusterLines := 0
+	for _, block := range blocks {
+		section, ok := block.(*slack.SectionBlock)
+		if !ok || section.Text == nil {
+			continue
+		}
+		assert.True(t, len(section.Text.Text) <= slackMaxSectionLength)
+		clusterLines += strings.Count(section.Text.Text, "running (deploy)")
+	}
+	assert.Equal(t, 500, clusterLines)
+}
+
+func Test_slackSections(t *testing.T) {
+	assert.Equal(t, []string{}, slackSections(nil, 10))
+	assert.Equal(t, []string{"aaaa\nbbbb", "cccc"}, slackSections([]string{"aaaa", "bbbb", "cccc"}, 10))
+}
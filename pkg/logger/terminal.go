@@ -16,12 +16,35 @@ type TerminalLogger struct {
 	spinnerArgs   []interface{}
 	isSilent      bool
 	isVerbose     bool
+	fields        Fields
 }
 
 func NewTerminalLogger() Logger {
 	return &TerminalLogger{}
 }
 
+func (l *TerminalLogger) WithFields(fields Fields) Logger {
+	return &TerminalLogger{
+		isSilent:  l.isSilent,
+		isVerbose: l.isVerbose,
+		fields:    l.fields.with(fields),
+	}
+}
+
+func (l *TerminalLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+// prefix is printed before every message, in the color of the cluster
+func (l *TerminalLogger) prefix() string {
+	cluster, ok := l.fields[FieldCluster]
+	if !ok {
+		return ""
+	}
+
+	return clusterColor(cluster).Sprintf("[%s] ", cluster)
+}
+
 func (l *TerminalLogger) Silence() {
 	if l == nil {
 		return
@@ -58,6 +81,7 @@ func (l *TerminalLogger) Debug(msg string, args ...interface{}) {
 	}
 
 	fmt.Printf("    ")
+	fmt.Print(l.prefix())
 	fmt.Println(fmt.Sprintf(msg, args...))
 	fmt.Println("")
 }
@@ -68,6 +92,7 @@ func (l *TerminalLogger) Info(msg string, args ...interface{}) {
 	}
 
 	fmt.Printf("    ")
+	fmt.Print(l.prefix())
 	fmt.Println(fmt.Sprintf(msg, args...))
 	fmt.Println("")
 }
@@ -83,6 +108,7 @@ func (l *TerminalLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
 	}
 
 	fmt.Printf("  • ")
+	fmt.Print(l.prefix())
 	fmt.Println(fmt.Sprintf(msg, args...))
 }
 
@@ -92,6 +118,7 @@ func (l *TerminalLogger) ChildActionWithoutSpinner(msg string, args ...interface
 	}
 
 	fmt.Printf("    • ")
+	fmt.Print(l.prefix())
 	fmt.Println(fmt.Sprintf(msg, args...))
 }
 
@@ -101,6 +128,7 @@ func (l *TerminalLogger) ActionWithSpinner(msg string, args ...interface{}) {
 	}
 
 	fmt.Printf("  • ")
+	fmt.Print(l.prefix())
 	fmt.Printf(msg, args...)
 
 	if isatty.IsTerminal(os.Stdout.Fd()) {
@@ -120,6 +148,7 @@ func (l *TerminalLogger) ActionWithSpinner(msg string, args ...interface{}) {
 				case <-time.After(time.Millisecond * 100):
 					fmt.Printf("\r")
 					fmt.Printf("  • ")
+					fmt.Print(l.prefix())
 					fmt.Printf(msg, args...)
 					fmt.Printf(" %s", s.Next())
 				}
@@ -134,6 +163,7 @@ func (l *TerminalLogger) ChildActionWithSpinner(msg string, args ...interface{})
 	}
 
 	fmt.Printf("    • ")
+	fmt.Print(l.prefix())
 	fmt.Printf(msg, args...)
 
 	if isatty.IsTerminal(os.Stdout.Fd()) {
@@ -153,6 +183,7 @@ func (l *TerminalLogger) ChildActionWithSpinner(msg string, args ...interface{})
 				case <-time.After(time.Millisecond * 100):
 					fmt.Printf("\r")
 					fmt.Printf("    • ")
+					fmt.Print(l.prefix())
 					fmt.Printf(msg, args...)
 					fmt.Printf(" %s", s.Next())
 				}
@@ -170,6 +201,7 @@ func (l *TerminalLogger) FinishChildSpinner() {
 
 	fmt.Printf("\r")
 	fmt.Printf("    • ")
+	fmt.Print(l.prefix())
 	fmt.Printf(l.spinnerMsg, l.spinnerArgs...)
 	green.Printf(" ✓")
 	fmt.Printf("  \n")
@@ -189,6 +221,7 @@ func (l *TerminalLogger) FinishSpinner() {
 
 	fmt.Printf("\r")
 	fmt.Printf("  • ")
+	fmt.Print(l.prefix())
 	fmt.Printf(l.spinnerMsg, l.spinnerArgs...)
 	green.Printf(" ✓")
 	fmt.Printf("  \n")
@@ -208,6 +241,7 @@ func (l *TerminalLogger) FinishSpinnerWithError() {
 
 	fmt.Printf("\r")
 	fmt.Printf("  • ")
+	fmt.Print(l.prefix())
 	fmt.Printf(l.spinnerMsg, l.spinnerArgs...)
 	red.Printf(" ✗")
 	fmt.Printf("  \n")
@@ -225,5 +259,6 @@ func (l *TerminalLogger) Error(err error) {
 
 	c := color.New(color.FgHiRed)
 	c.Printf("  • ")
+	fmt.Print(l.prefix())
 	c.Println(fmt.Sprintf("%#v", err))
 }
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/replicatedhq/kubectl-grid/pkg/watch"
+)
+
+// WatchLogger shows each message from a cluster as the last message of the cluster in the
+// watch table, instead of writing it to the terminal. Messages are also sent to next, when set
+type WatchLogger struct {
+	table  *watch.Table
+	fields Fields
+	next   Logger
+}
+
+// WithFields adds the fields to messages sent to next, the watch table already has a row for each cluster
+func (l *WatchLogger) WithFields(fields Fields) Logger {
+	child := &WatchLogger{
+		table:  l.table,
+		fields: l.fields.with(fields),
+	}
+	if l.next != nil {
+		child.next = l.next.WithFields(fields)
+	}
+	return child
+}
+
+func (l *WatchLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+func (l *WatchLogger) Silence() {
+	if l.next != nil {
+		l.next.Silence()
+	}
+}
+
+func (l *WatchLogger) Verbose() {
+	if l.next != nil {
+		l.next.Verbose()
+	}
+}
+
+func (l *WatchLogger) Initialize() {
+	if l.next != nil {
+		l.next.Initialize()
+	}
+}
+
+func (l *WatchLogger) Finish() {
+	if l.next != nil {
+		l.next.Finish()
+	}
+}
+
+func (l *WatchLogger) Debug(msg string, args ...interface{}) {
+	if l.next != nil {
+		l.next.Debug(msg, args...)
+	}
+}
+
+func (l *WatchLogger) Info(msg string, args ...interface{}) {
+	l.message(msg, args...)
+	if l.next != nil {
+		l.next.Info(msg, args...)
+	}
+}
+
+func (l *WatchLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
+	l.message(msg, args...)
+	if l.next != nil {
+		l.next.ActionWithoutSpinner(msg, args...)
+	}
+}
+
+func (l *WatchLogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
+	l.message(msg, args...)
+	if l.next != nil {
+		l.next.ChildActionWithoutSpinner(msg, args...)
+	}
+}
+
+// ActionWithSpinner doesn't start a spinner, the watch table has a spinner for each task
+func (l *WatchLogger) ActionWithSpinner(msg string, args ...interface{}) {
+	l.message(msg, args...)
+	if l.next != nil {
+		l.next.ActionWithSpinner(msg, args...)
+	}
+}
+
+func (l *WatchLogger) ChildActionWithSpinner(msg string, args ...interface{}) {
+	l.message(msg, args...)
+	if l.next != nil {
+		l.next.ChildActionWithSpinner(msg, args...)
+	}
+}
+
+func (l *WatchLogger) FinishChildSpinner() {
+	if l.next != nil {
+		l.next.FinishChildSpinner()
+	}
+}
+
+func (l *WatchLogger) FinishSpinner() {
+	if l.next != nil {
+		l.next.FinishSpinner()
+	}
+}
+
+func (l *WatchLogger) FinishSpinnerWithError() {
+	if l.next != nil {
+		l.next.FinishSpinnerWithError()
+	}
+}
+
+func (l *WatchLogger) Error(err error) {
+	l.message("error: %s", err.Error())
+	if l.next != nil {
+		l.next.Error(err)
+	}
+}
+
+// message is shown in the row of the cluster, messages that aren't from a cluster are only sent to next
+func (l *WatchLogger) message(msg string, args ...interface{}) {
+	cluster, ok := l.fields[FieldCluster]
+	if !ok {
+		return
+	}
+
+	l.table.Message(cluster, fmt.Sprintf(msg, args...))
+}
+
+// TaskQueued, TaskStarted and TaskFinished notify next, the watch table observes the tasks itself
+func (l *WatchLogger) TaskQueued(cluster string) {
+	if observer, ok := l.next.(orchestrator.Observer); ok {
+		observer.TaskQueued(cluster)
+	}
+}
+
+func (l *WatchLogger) TaskStarted(cluster string) {
+	if observer, ok := l.next.(orchestrator.Observer); ok {
+		observer.TaskStarted(cluster)
+	}
+}
+
+func (l *WatchLogger) TaskFinished(cluster string, err error) {
+	if observer, ok := l.next.(orchestrator.Observer); ok {
+		observer.TaskFinished(cluster, err)
+	}
+}
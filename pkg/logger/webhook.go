@@ -0,0 +1,463 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+const (
+	defaultWebhookRetries = 3
+	defaultWebhookBackoff = time.Second
+	// maxWebhookRetryAfter limits how long a Retry-After header holds up the events behind it
+	maxWebhookRetryAfter = 30 * time.Second
+	// webhookQueueSize is how many events can wait to be sent before logging blocks
+	webhookQueueSize = 100
+	// discord rejects messages that are longer than this
+	maxDiscordMessageLength = 2000
+)
+
+// WebhookLogger posts each event to a url, as a JSON event or formatted as a Microsoft Teams or
+// Discord message. The events are posted in order in the background, and Finish waits for them
+type WebhookLogger struct {
+	url           string
+	headers       map[string]string
+	format        string
+	retries       int
+	backoff       time.Duration
+	maxRetryAfter time.Duration
+	client        *http.Client
+	now           func() time.Time
+
+	// queue is shared with child loggers
+	queue *webhookQueue
+
+	spinner      *jsonSpinner
+	childSpinner *jsonSpinner
+
+	isSilent  bool
+	isVerbose bool
+	fields    Fields
+}
+
+func NewWebhookLogger(loggerSpec types.WebhookLoggerSpec) Logger {
+	l := &WebhookLogger{
+		headers:       map[string]string{},
+		format:        loggerSpec.Format,
+		retries:       defaultWebhookRetries,
+		backoff:       defaultWebhookBackoff,
+		maxRetryAfter: maxWebhookRetryAfter,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		now:           time.Now,
+		queue:         &webhookQueue{},
+	}
+	if l.format == "" {
+		l.format = types.WebhookFormatJSON
+	}
+	if loggerSpec.Retries != nil {
+		l.retries = *loggerSpec.Retries
+	}
+
+	switch l.format {
+	case types.WebhookFormatJSON, types.WebhookFormatTeams, types.WebhookFormatDiscord:
+	default:
+		log.Println("unknown format for webhook logger", l.format)
+		l.isSilent = true
+	}
+
+	url, err := loggerSpec.URL.String()
+	if err != nil {
+		log.Println("failed to get url for webhook logger", err)
+		l.isSilent = true
+	} else {
+		l.url = url
+	}
+
+	for name, value := range loggerSpec.Headers {
+		v, err := value.String()
+		if err != nil {
+			log.Println("failed to get header for webhook logger", name, err)
+			l.isSilent = true
+			continue
+		}
+		l.headers[name] = v
+	}
+
+	return l
+}
+
+func (l *WebhookLogger) WithFields(fields Fields) Logger {
+	return &WebhookLogger{
+		url:           l.url,
+		headers:       l.headers,
+		format:        l.format,
+		retries:       l.retries,
+		backoff:       l.backoff,
+		maxRetryAfter: l.maxRetryAfter,
+		client:        l.client,
+		now:           l.now,
+		queue:         l.queue,
+		isSilent:      l.isSilent,
+		isVerbose:     l.isVerbose,
+		fields:        l.fields.with(fields),
+	}
+}
+
+func (l *WebhookLogger) WithCluster(cluster string) Logger {
+	return l.WithFields(Fields{FieldCluster: cluster})
+}
+
+func (l *WebhookLogger) Silence() {
+	if l == nil {
+		return
+	}
+	l.isSilent = true
+}
+
+func (l *WebhookLogger) Verbose() {
+	if l == nil {
+		return
+	}
+	l.isVerbose = true
+}
+
+func (l *WebhookLogger) Initialize() {
+}
+
+// Finish waits for the events that were logged to be sent. Events that are logged after
+// Finish are not sent
+func (l *WebhookLogger) Finish() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.queue.close()
+}
+
+func (l *WebhookLogger) Debug(msg string, args ...interface{}) {
+	if l == nil || l.isSilent || !l.isVerbose {
+		return
+	}
+
+	l.send(l.newEvent(LevelDebug, fmt.Sprintf(msg, args...)))
+}
+
+func (l *WebhookLogger) Info(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.send(l.newEvent(LevelInfo, fmt.Sprintf(msg, args...)))
+}
+
+func (l *WebhookLogger) ActionWithoutSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent || msg == "" {
+		return
+	}
+
+	l.send(l.newEvent(LevelInfo, fmt.Sprintf(msg, args...)))
+}
+
+func (l *WebhookLogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
+	l.ActionWithoutSpinner(msg, args...)
+}
+
+func (l *WebhookLogger) ActionWithSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.spinner = l.startSpinner(fmt.Sprintf(msg, args...))
+}
+
+func (l *WebhookLogger) ChildActionWithSpinner(msg string, args ...interface{}) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.childSpinner = l.startSpinner(fmt.Sprintf(msg, args...))
+}
+
+func (l *WebhookLogger) FinishChildSpinner() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.childSpinner, LevelInfo)
+	l.childSpinner = nil
+}
+
+func (l *WebhookLogger) FinishSpinner() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.spinner, LevelInfo)
+	l.spinner = nil
+}
+
+func (l *WebhookLogger) FinishSpinnerWithError() {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	l.finishSpinner(l.spinner, LevelError)
+	l.spinner = nil
+}
+
+func (l *WebhookLogger) Error(err error) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	event := l.newEvent(LevelError, "")
+	event.Error = err.Error()
+	l.send(event)
+}
+
+func (l *WebhookLogger) TaskQueued(cluster string) {
+}
+
+func (l *WebhookLogger) TaskStarted(cluster string) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	event := newJSONEvent(l.now(), l.fields.with(Fields{FieldCluster: cluster}), LevelInfo, "started")
+	l.send(event)
+}
+
+func (l *WebhookLogger) TaskFinished(cluster string, err error) {
+	if l == nil || l.isSilent {
+		return
+	}
+
+	event := newJSONEvent(l.now(), l.fields.with(Fields{FieldCluster: cluster}), LevelInfo, "finished")
+	if err != nil {
+		event.Level = LevelError
+		event.Message = "failed"
+		event.Error = err.Error()
+	}
+	l.send(event)
+}
+
+func (l *WebhookLogger) newEvent(level string, message string) JSONEvent {
+	return newJSONEvent(l.now(), l.fields, level, message)
+}
+
+func (l *WebhookLogger) startSpinner(message string) *jsonSpinner {
+	s := &jsonSpinner{
+		message:   message,
+		startedAt: l.now(),
+	}
+
+	event := l.newEvent(LevelInfo, message)
+	event.Event = EventStart
+	l.send(event)
+
+	return s
+}
+
+func (l *WebhookLogger) finishSpinner(s *jsonSpinner, level string) {
+	if s == nil {
+		return
+	}
+
+	event := l.newEvent(level, s.message)
+	event.Event = EventFinish
+	duration := event.Timestamp.Sub(s.startedAt).Seconds()
+	event.DurationSeconds = &duration
+	l.send(event)
+}
+
+// webhookQueue holds the events until they're posted by a single goroutine, so that logging
+// doesn't wait for the webhook and the events are posted in the order they were logged
+type webhookQueue struct {
+	// mu is held for reading while an event is queued, so that the queue isn't closed under it
+	mu     sync.RWMutex
+	start  sync.Once
+	events chan []byte
+	done   chan struct{}
+	closed bool
+}
+
+// add queues the body, starting the goroutine that posts the events when it's the first one
+func (q *webhookQueue) add(body []byte, deliver func(body []byte)) {
+	q.start.Do(func() {
+		q.events = make(chan []byte, webhookQueueSize)
+		q.done = make(chan struct{})
+		go func() {
+			defer close(q.done)
+			for body := range q.events {
+				deliver(body)
+			}
+		}()
+	})
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		log.Println("webhook logger is finished, not sending event")
+		return
+	}
+	q.events <- body
+}
+
+// close waits for the queued events to be posted
+func (q *webhookQueue) close() {
+	// nothing was queued when the goroutine wasn't started
+	q.start.Do(func() {})
+
+	q.mu.Lock()
+	if q.closed || q.events == nil {
+		q.closed = true
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.events)
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// send queues the event to be posted
+func (l *WebhookLogger) send(event JSONEvent) {
+	body, err := formatWebhookEvent(l.format, event)
+	if err != nil {
+		log.Println("failed to format webhook event", err)
+		return
+	}
+
+	l.queue.add(body, l.deliver)
+}
+
+// deliver posts the body, retrying with exponential backoff. Failures are only logged, so
+// that a webhook that is down doesn't fail the grid operation
+func (l *WebhookLogger) deliver(body []byte) {
+	backoff := l.backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := l.post(body)
+		if err == nil {
+			return
+		}
+		if retryAfter < 0 || attempt >= l.retries {
+			log.Println("failed to send webhook event", err)
+			return
+		}
+
+		if retryAfter > l.maxRetryAfter {
+			retryAfter = l.maxRetryAfter
+		}
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends the body once. The duration is how long to wait before retrying, 0 to use the
+// backoff, or negative when the request should not be retried
+func (l *WebhookLogger) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest("POST", l.url, bytes.NewReader(body))
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range l.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to post")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = errors.Errorf("unexpected status code %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(seconds) * time.Second, err
+	}
+	if resp.StatusCode >= 500 {
+		return 0, err
+	}
+	return -1, err
+}
+
+// formatWebhookEvent returns the body to post for the event
+func formatWebhookEvent(format string, event JSONEvent) ([]byte, error) {
+	switch format {
+	case types.WebhookFormatTeams:
+		themeColor := "0076D7"
+		if event.Level == LevelError {
+			themeColor = "D70000"
+		}
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    event.Message,
+			"themeColor": themeColor,
+			"text":       webhookEventText(event),
+		})
+
+	case types.WebhookFormatDiscord:
+		text := webhookEventText(event)
+		if runes := []rune(text); len(runes) > maxDiscordMessageLength {
+			text = string(runes[:maxDiscordMessageLength-3]) + "..."
+		}
+		return json.Marshal(map[string]string{
+			"content": text,
+		})
+	}
+
+	return json.Marshal(event)
+}
+
+// webhookEventText formats the event as a markdown message, such as
+// "my-grid / **my-cluster** (vpc): Creating VPC for EKS cluster"
+func webhookEventText(event JSONEvent) string {
+	source := []string{}
+	if event.Grid != "" {
+		source = append(source, event.Grid)
+	}
+	if event.Cluster != "" {
+		source = append(source, fmt.Sprintf("**%s**", event.Cluster))
+	}
+
+	text := strings.Join(source, " / ")
+	if event.Step != "" {
+		text = fmt.Sprintf("%s (%s)", text, event.Step)
+	}
+
+	message := event.Message
+	if event.DurationSeconds != nil {
+		message = fmt.Sprintf("%s (%s in %s)", message, event.Event, time.Duration(*event.DurationSeconds*float64(time.Second)).Round(time.Second))
+	}
+	if event.Error != "" {
+		message = strings.TrimSpace(fmt.Sprintf("%s error: %s", message, event.Error))
+	}
+
+	if text == "" {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", text, message)
+}
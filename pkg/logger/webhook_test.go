@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WebhookLogger(t *testing.T) {
+	mu := sync.Mutex{}
+	statusCodes := []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}
+	bodies := [][]byte{}
+	headers := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		headers = append(headers, r.Header.Get("Authorization"))
+
+		statusCode := statusCodes[0]
+		if len(statusCodes) > 1 {
+			statusCodes = statusCodes[1:]
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	l := NewWebhookLogger(types.WebhookLoggerSpec{
+		URL: types.ValueOrValueFrom{Value: server.URL},
+		Headers: map[string]types.ValueOrValueFrom{
+			"Authorization": {Value: "Bearer token"},
+		},
+	}).(*WebhookLogger)
+	l.backoff = time.Millisecond
+
+	l.WithFields(Fields{FieldGrid: "my-grid", FieldCluster: "my-cluster"}).Info("Creating VPC")
+	l.Finish()
+
+	// the first two attempts fail and are retried
+	require.Len(t, bodies, 3)
+	assert.Equal(t, []string{"Bearer token", "Bearer token", "Bearer token"}, headers)
+
+	event := JSONEvent{}
+	require.NoError(t, json.Unmarshal(bodies[2], &event))
+	assert.Equal(t, LevelInfo, event.Level)
+	assert.Equal(t, "my-grid", event.Grid)
+	assert.Equal(t, "my-cluster", event.Cluster)
+	assert.Equal(t, "Creating VPC", event.Message)
+}
+
+func Test_WebhookLoggerNoRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	l := NewWebhookLogger(types.WebhookLoggerSpec{
+		URL: types.ValueOrValueFrom{Value: server.URL},
+	}).(*WebhookLogger)
+	l.backoff = time.Millisecond
+
+	// a request that is rejected won't succeed when it's retried
+	l.Info("Creating VPC")
+	l.Finish()
+	assert.Equal(t, 1, requests)
+}
+
+func Test_WebhookLoggerOrderAndRetryAfter(t *testing.T) {
+	mu := sync.Mutex{}
+	messages := []string{}
+	rateLimited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// the first request is rate limited for longer than the logger waits
+		if !rateLimited {
+			rateLimited = true
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		event := JSONEvent{}
+		json.NewDecoder(r.Body).Decode(&event)
+		messages = append(messages, event.Message)
+	}))
+	defer server.Close()
+
+	l := NewWebhookLogger(types.WebhookLoggerSpec{
+		URL: types.ValueOrValueFrom{Value: server.URL},
+	}).(*WebhookLogger)
+	l.backoff = time.Millisecond
+	l.maxRetryAfter = 10 * time.Millisecond
+
+	for i := 0; i < 5; i++ {
+		l.Info("event %d", i)
+	}
+	l.Finish()
+
+	// events that are logged after finish are not sent
+	l.Info("event 5")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"event 0", "event 1", "event 2", "event 3", "event 4"}, messages)
+}
+
+func Test_formatWebhookEvent(t *testing.T) {
+	duration := float64(90)
+	tests := []struct {
+		name   string
+		format string
+		event  JSONEvent
+		want   map[string]string
+	}{
+		{
+			name:   "teams",
+			format: types.WebhookFormatTeams,
+			event: JSONEvent{
+				Level:   LevelInfo,
+				Grid:    "my-grid",
+				Cluster: "my-cluster",
+				Step:    "vpc",
+				Message: "Creating VPC",
+			},
+			want: map[string]string{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "Creating VPC",
+				"themeColor": "0076D7",
+				"text":       "my-grid / **my-cluster** (vpc): Creating VPC",
+			},
+		},
+		{
+			name:   "teams error",
+			format: types.WebhookFormatTeams,
+			event: JSONEvent{
+				Level:   LevelError,
+				Cluster: "my-cluster",
+				Error:   "timed out",
+			},
+			want: map[string]string{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "",
+				"themeColor": "D70000",
+				"text":       "**my-cluster**: error: timed out",
+			},
+		},
+		{
+			name:   "discord spinner",
+			format: types.WebhookFormatDiscord,
+			event: JSONEvent{
+				Level:           LevelInfo,
+				Cluster:         "my-cluster",
+				Message:         "Waiting for control plane",
+				Event:           EventFinish,
+				DurationSeconds: &duration,
+			},
+			want: map[string]string{
+				"content": "**my-cluster**: Waiting for control plane (finish in 1m30s)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := formatWebhookEvent(test.format, test.event)
+			require.NoError(t, err)
+
+			got := map[string]string{}
+			require.NoError(t, json.Unmarshal(body, &got))
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_formatWebhookEventDiscordTruncate(t *testing.T) {
+	body, err := formatWebhookEvent(types.WebhookFormatDiscord, JSONEvent{
+		Message: strings.Repeat("✓", 3000),
+	})
+	require.NoError(t, err)
+
+	got := map[string]string{}
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.True(t, utf8.ValidString(got["content"]))
+	assert.Equal(t, maxDiscordMessageLength, utf8.RuneCountInString(got["content"]))
+	assert.True(t, strings.HasSuffix(got["content"], "✓..."))
+}
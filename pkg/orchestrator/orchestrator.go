@@ -0,0 +1,216 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/tracing"
+)
+
+// Task is the work to run on a single cluster
+type Task struct {
+	// Cluster is the name of the cluster, used to report errors
+	Cluster string
+	Run     func(ctx context.Context) error
+}
+
+type Result struct {
+	Cluster    string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Err        error
+}
+
+type Options struct {
+	// Parallelism is the maximum number of tasks to run at once, or 0 for no limit
+	Parallelism int
+}
+
+// Observer is notified as each task is queued, starts and finishes
+type Observer interface {
+	TaskQueued(cluster string)
+	TaskStarted(cluster string)
+	TaskFinished(cluster string, err error)
+}
+
+type observerKey struct{}
+type clusterKey struct{}
+
+// WithObserver returns a context that will notify the observer of the tasks that are run with
+// it, as well as any observers already in ctx
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	existing, _ := ctx.Value(observerKey{}).(observers)
+	o := append(observers{}, existing...)
+	return context.WithValue(ctx, observerKey{}, append(o, observer))
+}
+
+func observerFromContext(ctx context.Context) Observer {
+	o, _ := ctx.Value(observerKey{}).(observers)
+	if len(o) == 0 {
+		return nil
+	}
+	return o
+}
+
+// observers notifies each observer in order
+type observers []Observer
+
+func (o observers) TaskQueued(cluster string) {
+	for _, observer := range o {
+		observer.TaskQueued(cluster)
+	}
+}
+
+func (o observers) TaskStarted(cluster string) {
+	for _, observer := range o {
+		observer.TaskStarted(cluster)
+	}
+}
+
+func (o observers) TaskFinished(cluster string, err error) {
+	for _, observer := range o {
+		observer.TaskFinished(cluster, err)
+	}
+}
+
+// ClusterFromContext returns the cluster of the task that ctx was passed to, or "" when
+// ctx was not passed to a task
+func ClusterFromContext(ctx context.Context) string {
+	cluster, _ := ctx.Value(clusterKey{}).(string)
+	return cluster
+}
+
+// ClusterError is the error from the task on a single cluster
+type ClusterError struct {
+	Cluster string
+	Err     error
+}
+
+func (e *ClusterError) Error() string {
+	return fmt.Sprintf("cluster %s: %s", e.Cluster, e.Err.Error())
+}
+
+func (e *ClusterError) Cause() error {
+	return e.Err
+}
+
+func (e *ClusterError) Unwrap() error {
+	return e.Err
+}
+
+// Error is returned when the task failed on one or more clusters
+type Error struct {
+	Errors []*ClusterError
+	Total  int
+}
+
+func (e *Error) Error() string {
+	messages := []string{}
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("%d of %d clusters failed: %s", len(e.Errors), e.Total, strings.Join(messages, "; "))
+}
+
+// FailedClusters returns the names of the clusters that failed
+func (e *Error) FailedClusters() []string {
+	clusters := []string{}
+	for _, err := range e.Errors {
+		clusters = append(clusters, err.Cluster)
+	}
+
+	return clusters
+}
+
+// Run will run each task, with at most opts.Parallelism running at once, and wait for all of
+// them to finish. Tasks that have not started when the context is cancelled are not run.
+// The results are in the same order as the tasks, and the error is an *Error if any task failed
+func Run(ctx context.Context, tasks []Task, opts Options) ([]*Result, error) {
+	results := make([]*Result, len(tasks))
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 || parallelism > len(tasks) {
+		parallelism = len(tasks)
+	}
+	sem := make(chan struct{}, parallelism)
+
+	observer := observerFromContext(ctx)
+	if observer != nil {
+		for _, task := range tasks {
+			observer.TaskQueued(task.Cluster)
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	for i, task := range tasks {
+		results[i] = &Result{
+			Cluster: task.Cluster,
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			if observer != nil {
+				observer.TaskFinished(task.Cluster, results[i].Err)
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(task Task, result *Result) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if observer != nil {
+				observer.TaskStarted(task.Cluster)
+			}
+			// each task is a span, so that the steps on the cluster are grouped under it
+			taskCtx, span := tracing.Start(context.WithValue(ctx, clusterKey{}, task.Cluster), fmt.Sprintf("cluster %s", task.Cluster), tracing.ClusterKey.String(task.Cluster))
+			result.StartedAt = time.Now()
+			result.Err = runTask(taskCtx, task)
+			result.FinishedAt = time.Now()
+			tracing.End(span, result.Err)
+			if observer != nil {
+				observer.TaskFinished(task.Cluster, result.Err)
+			}
+		}(task, results[i])
+	}
+
+	wg.Wait()
+
+	combined := &Error{
+		Total: len(tasks),
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			combined.Errors = append(combined.Errors, &ClusterError{
+				Cluster: result.Cluster,
+				Err:     result.Err,
+			})
+		}
+	}
+
+	if len(combined.Errors) > 0 {
+		return results, combined
+	}
+
+	return results, nil
+}
+
+// runTask returns a panic in the task as an error, so that one cluster can't take down the others
+func runTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return task.Run(ctx)
+}
@@ -0,0 +1,210 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name           string
+		tasks          func(running *int32, maxRunning *int32) []Task
+		parallelism    int
+		expectedFailed []string
+	}{
+		{
+			name: "all succeed",
+			tasks: func(running *int32, maxRunning *int32) []Task {
+				return fakeTasks(running, maxRunning, map[string]error{"a": nil, "b": nil, "c": nil})
+			},
+			expectedFailed: nil,
+		},
+		{
+			name: "partial failure",
+			tasks: func(running *int32, maxRunning *int32) []Task {
+				return fakeTasks(running, maxRunning, map[string]error{"a": nil, "b": errFailed, "c": nil})
+			},
+			expectedFailed: []string{"b"},
+		},
+		{
+			name: "panic is an error",
+			tasks: func(running *int32, maxRunning *int32) []Task {
+				return []Task{
+					{Cluster: "a", Run: func(ctx context.Context) error { panic("boom") }},
+					{Cluster: "b", Run: func(ctx context.Context) error { return nil }},
+				}
+			},
+			expectedFailed: []string{"a"},
+		},
+		{
+			name: "bounded parallelism",
+			tasks: func(running *int32, maxRunning *int32) []Task {
+				return fakeTasks(running, maxRunning, map[string]error{"a": nil, "b": nil, "c": nil, "d": nil, "e": nil})
+			},
+			parallelism:    2,
+			expectedFailed: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var running, maxRunning int32
+			tasks := test.tasks(&running, &maxRunning)
+
+			results, err := Run(context.Background(), tasks, Options{Parallelism: test.parallelism})
+			require.Len(t, results, len(tasks))
+			for i, result := range results {
+				assert.Equal(t, tasks[i].Cluster, result.Cluster)
+			}
+
+			if test.parallelism > 0 {
+				assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(test.parallelism))
+			}
+
+			if test.expectedFailed == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var combined *Error
+			require.True(t, errors.As(err, &combined))
+			assert.Equal(t, test.expectedFailed, combined.FailedClusters())
+			assert.Equal(t, len(tasks), combined.Total)
+		})
+	}
+}
+
+func Test_RunCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	var ran int32
+	tasks := []Task{
+		{
+			Cluster: "a",
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&ran, 1)
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		tasks = append(tasks, Task{
+			Cluster: fmt.Sprintf("queued-%d", i),
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&ran, 1)
+				return nil
+			},
+		})
+	}
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	results, err := Run(ctx, tasks, Options{Parallelism: 1})
+	require.Error(t, err)
+
+	// only the first task started, the queued tasks were cancelled before they ran
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ran))
+	for _, result := range results {
+		assert.True(t, errors.Is(result.Err, context.Canceled))
+	}
+
+	var combined *Error
+	require.True(t, errors.As(err, &combined))
+	assert.Len(t, combined.Errors, 4)
+}
+
+func fakeTasks(running *int32, maxRunning *int32, clusters map[string]error) []Task {
+	tasks := []Task{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		taskErr, ok := clusters[name]
+		if !ok {
+			continue
+		}
+
+		tasks = append(tasks, Task{
+			Cluster: name,
+			Run: func(ctx context.Context) error {
+				n := atomic.AddInt32(running, 1)
+				defer atomic.AddInt32(running, -1)
+				for {
+					max := atomic.LoadInt32(maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(maxRunning, max, n) {
+						break
+					}
+				}
+
+				time.Sleep(10 * time.Millisecond)
+				return taskErr
+			},
+		})
+	}
+
+	return tasks
+}
+
+type fakeObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *fakeObserver) TaskQueued(cluster string) {
+	o.record("queued " + cluster)
+}
+
+func (o *fakeObserver) TaskStarted(cluster string) {
+	o.record("started " + cluster)
+}
+
+func (o *fakeObserver) TaskFinished(cluster string, err error) {
+	if err != nil {
+		o.record("failed " + cluster)
+		return
+	}
+	o.record("finished " + cluster)
+}
+
+func (o *fakeObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func Test_RunObserver(t *testing.T) {
+	observer, other := &fakeObserver{}, &fakeObserver{}
+	ctx := WithObserver(WithObserver(context.Background(), observer), other)
+
+	clusters := []string{}
+	tasks := []Task{
+		{Cluster: "a", Run: func(ctx context.Context) error {
+			clusters = append(clusters, ClusterFromContext(ctx))
+			return nil
+		}},
+		{Cluster: "b", Run: func(ctx context.Context) error {
+			clusters = append(clusters, ClusterFromContext(ctx))
+			return errors.New("failed")
+		}},
+	}
+
+	_, err := Run(ctx, tasks, Options{Parallelism: 1})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, clusters)
+	assert.Equal(t, []string{"queued a", "queued b", "started a", "finished a", "started b", "failed b"}, observer.events)
+	assert.Equal(t, observer.events, other.events)
+}
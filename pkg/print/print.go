@@ -1,6 +1,7 @@
 package print
 
 import (
+	"io"
 	"os"
 	"text/tabwriter"
 )
@@ -13,5 +14,9 @@ const (
 )
 
 func NewTabWriter() *tabwriter.Writer {
-	return tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent)
+	return NewTabWriterTo(os.Stdout)
+}
+
+func NewTabWriterTo(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent)
 }
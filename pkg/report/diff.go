@@ -0,0 +1,220 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/experiment"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+)
+
+// ReadinessThreshold is the threshold name for the time the application took to be ready
+const ReadinessThreshold = "readiness"
+
+// DefaultThresholds are the percent increases allowed before a change is a regression
+var DefaultThresholds = Thresholds{
+	ReadinessThreshold:        50,
+	"http_req_duration.p(95)": 20,
+}
+
+// Thresholds is a map of "readiness", or a k6 metric in the form "<metric>.<stat>", to
+// the percent increase allowed
+type Thresholds map[string]float64
+
+// ParseThresholds parses thresholds in the form name=percent, for example
+// http_req_duration.p(95)=20%, and adds them to the defaults
+func ParseThresholds(values []string) (Thresholds, error) {
+	thresholds := Thresholds{}
+	for name, percent := range DefaultThresholds {
+		thresholds[name] = percent
+	}
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("threshold %q is not in the form name=percent", value)
+		}
+
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(parts[1], "%"), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse threshold %q", value)
+		}
+		thresholds[parts[0]] = percent
+	}
+
+	return thresholds, nil
+}
+
+type Change struct {
+	Cluster    string `json:"cluster"`
+	Check      string `json:"check"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	Change     string `json:"change,omitempty"`
+	Regression bool   `json:"regression"`
+}
+
+// Diff compares the results on each cluster in run b against run a
+func Diff(a *runs.Run, b *runs.Run, thresholds Thresholds) ([]Change, error) {
+	changes := []Change{}
+
+	for _, clusterA := range a.Clusters {
+		clusterB := b.GetCluster(clusterA.Name)
+		if clusterB == nil {
+			changes = append(changes, Change{
+				Cluster:    clusterA.Name,
+				Check:      "outcome",
+				Before:     clusterA.Outcome,
+				After:      "missing",
+				Regression: true,
+			})
+			continue
+		}
+
+		changes = append(changes, Change{
+			Cluster:    clusterA.Name,
+			Check:      "outcome",
+			Before:     clusterA.Outcome,
+			After:      clusterB.Outcome,
+			Regression: clusterA.Outcome == runs.OutcomeSucceeded && clusterB.Outcome != runs.OutcomeSucceeded,
+		})
+
+		changes = append(changes, diffPreflights(clusterA, clusterB)...)
+
+		if percent, ok := thresholds[ReadinessThreshold]; ok {
+			readinessA, readinessB := findStep(clusterA, types.StepReadiness), findStep(clusterB, types.StepReadiness)
+			if readinessA != nil && readinessB != nil && readinessA.Error == "" && readinessB.Error == "" {
+				change := diffValue(clusterA.Name, ReadinessThreshold, readinessA.Duration().Seconds(), readinessB.Duration().Seconds(), percent)
+				change.Before = formatDuration(readinessA.Duration())
+				change.After = formatDuration(readinessB.Duration())
+				changes = append(changes, change)
+			}
+		}
+
+		k6Changes, err := diffK6Summaries(clusterA, clusterB, thresholds)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compare k6 summaries for cluster %s", clusterA.Name)
+		}
+		changes = append(changes, k6Changes...)
+	}
+
+	return changes, nil
+}
+
+// Regressions returns the number of changes that are regressions
+func Regressions(changes []Change) int {
+	count := 0
+	for _, change := range changes {
+		if change.Regression {
+			count++
+		}
+	}
+
+	return count
+}
+
+// diffPreflights returns the preflight checks that changed state
+func diffPreflights(clusterA *runs.ClusterRun, clusterB *runs.ClusterRun) []Change {
+	severity := map[string]int{
+		types.PreflightStatePass: 0,
+		types.PreflightStateWarn: 1,
+		types.PreflightStateFail: 2,
+	}
+
+	changes := []Change{}
+	for _, resultA := range clusterA.PreflightResults {
+		for _, resultB := range clusterB.PreflightResults {
+			if resultA.Check != resultB.Check || resultA.State == resultB.State {
+				continue
+			}
+
+			changes = append(changes, Change{
+				Cluster:    clusterA.Name,
+				Check:      fmt.Sprintf("preflight %s", resultA.Check),
+				Before:     resultA.State,
+				After:      resultB.State,
+				Regression: severity[resultB.State] > severity[resultA.State],
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffK6Summaries(clusterA *runs.ClusterRun, clusterB *runs.ClusterRun, thresholds Thresholds) ([]Change, error) {
+	summaryA, err := readClusterK6Summary(clusterA)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read first k6 summary")
+	}
+	summaryB, err := readClusterK6Summary(clusterB)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read second k6 summary")
+	}
+	if summaryA == nil || summaryB == nil {
+		return nil, nil
+	}
+
+	changes := []Change{}
+	for _, name := range sortedThresholdNames(thresholds) {
+		parts := strings.SplitN(name, ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		valueA, okA := summaryA.Value(parts[0], parts[1])
+		valueB, okB := summaryB.Value(parts[0], parts[1])
+		if !okA || !okB {
+			continue
+		}
+
+		changes = append(changes, diffValue(clusterA.Name, name, valueA, valueB, thresholds[name]))
+	}
+
+	return changes, nil
+}
+
+func readClusterK6Summary(c *runs.ClusterRun) (*experiment.K6Summary, error) {
+	for _, artifact := range c.Artifacts {
+		if experiment.IsK6Summary(artifact) {
+			return experiment.ReadK6Summary(artifact)
+		}
+	}
+
+	return nil, nil
+}
+
+// diffValue is a regression when the value increased by more than the percent
+func diffValue(clusterName string, check string, before float64, after float64, percent float64) Change {
+	change := Change{
+		Cluster: clusterName,
+		Check:   check,
+		Before:  strconv.FormatFloat(before, 'f', 2, 64),
+		After:   strconv.FormatFloat(after, 'f', 2, 64),
+	}
+
+	if before == 0 {
+		return change
+	}
+
+	increase := (after - before) / before * 100
+	change.Change = fmt.Sprintf("%+.1f%%", increase)
+	change.Regression = increase > percent
+
+	return change
+}
+
+func sortedThresholdNames(thresholds Thresholds) []string {
+	names := []string{}
+	for name := range thresholds {
+		if name != ReadinessThreshold {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds([]string{"http_req_duration.p(95)=10%", "http_req_duration.avg=15"})
+	require.NoError(t, err)
+	assert.Equal(t, Thresholds{
+		ReadinessThreshold:        50,
+		"http_req_duration.p(95)": 10,
+		"http_req_duration.avg":   15,
+	}, thresholds)
+
+	_, err = ParseThresholds([]string{"readiness"})
+	assert.Error(t, err)
+}
+
+func Test_Diff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diff")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeSummary := func(name string, p95 float64) string {
+		path := filepath.Join(dir, name, "cluster-a-k6-summary.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		err := ioutil.WriteFile(path, []byte(fmt.Sprintf(`{"metrics":{"http_req_duration":{"avg":10,"p(95)":%f}}}`, p95)), 0644)
+		require.NoError(t, err)
+		return path
+	}
+
+	startedAt := time.Now()
+	readiness := func(seconds int) []types.StepResult {
+		return []types.StepResult{
+			{
+				Name:       types.StepReadiness,
+				StartedAt:  startedAt,
+				FinishedAt: startedAt.Add(time.Duration(seconds) * time.Second),
+			},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		a                   *runs.Run
+		b                   *runs.Run
+		expectedRegressions []string
+	}{
+		{
+			name: "no regressions",
+			a: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded, Steps: readiness(100), Artifacts: []string{writeSummary("a1", 100)}},
+			}},
+			b: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded, Steps: readiness(120), Artifacts: []string{writeSummary("b1", 110)}},
+			}},
+			expectedRegressions: []string{},
+		},
+		{
+			name: "p95 and readiness regressions",
+			a: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded, Steps: readiness(100), Artifacts: []string{writeSummary("a2", 100)}},
+			}},
+			b: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded, Steps: readiness(200), Artifacts: []string{writeSummary("b2", 125)}},
+			}},
+			expectedRegressions: []string{"cluster-a readiness", "cluster-a http_req_duration.p(95)"},
+		},
+		{
+			name: "outcome and preflight regressions",
+			a: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded, PreflightResults: []types.PreflightResult{{Check: "memory", State: types.PreflightStatePass}}},
+				{Name: "cluster-b", Outcome: runs.OutcomeSucceeded},
+			}},
+			b: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeFailed, PreflightResults: []types.PreflightResult{{Check: "memory", State: types.PreflightStateWarn}}},
+			}},
+			expectedRegressions: []string{"cluster-a outcome", "cluster-a preflight memory", "cluster-b outcome"},
+		},
+		{
+			name: "fixed cluster",
+			a: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeFailed},
+			}},
+			b: &runs.Run{Clusters: []*runs.ClusterRun{
+				{Name: "cluster-a", Outcome: runs.OutcomeSucceeded},
+			}},
+			expectedRegressions: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes, err := Diff(test.a, test.b, DefaultThresholds)
+			require.NoError(t, err)
+
+			regressions := []string{}
+			for _, change := range changes {
+				if change.Regression {
+					regressions = append(regressions, fmt.Sprintf("%s %s", change.Cluster, change.Check))
+				}
+			}
+			assert.Equal(t, test.expectedRegressions, regressions)
+			assert.Equal(t, len(test.expectedRegressions), Regressions(changes))
+		})
+	}
+}
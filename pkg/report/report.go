@@ -0,0 +1,222 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kubectl-grid/pkg/experiment"
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+)
+
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+
+	StatePassed = "passed"
+	StateFailed = "failed"
+)
+
+type Report struct {
+	Run         *runs.Run
+	Outcome     string
+	Duration    string
+	Steps       []string
+	Clusters    []ClusterReport
+	GeneratedAt time.Time
+}
+
+type ClusterReport struct {
+	Name     string
+	Provider string
+	Region   string
+	Version  string
+	Outcome  string
+	// Cells are the results of the steps, in the same order as Report.Steps
+	Cells          []Cell
+	Errors         []StepError
+	K6Summaries    []K6Summary
+	SupportBundles []string
+	Artifacts      []string
+}
+
+// Cell is the result of a step on a cluster. State is empty if the step did not run
+type Cell struct {
+	State    string
+	Duration string
+}
+
+type StepError struct {
+	Step    string
+	Message string
+}
+
+type K6Summary struct {
+	Path               string
+	Requests           string
+	RequestDurationAvg string
+	RequestDurationP95 string
+	ChecksFailed       string
+}
+
+// Generate will write the report for the run in the format
+func Generate(r *runs.Run, format string, w io.Writer) error {
+	report, err := buildReport(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to build report")
+	}
+
+	switch format {
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("report").Parse(htmlTemplate)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse html template")
+		}
+		if err := tmpl.Execute(w, report); err != nil {
+			return errors.Wrap(err, "failed to render html report")
+		}
+	case FormatMarkdown:
+		tmpl, err := texttemplate.New("report").Funcs(texttemplate.FuncMap{
+			"cell": markdownCell,
+		}).Parse(markdownTemplate)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse markdown template")
+		}
+		if err := tmpl.Execute(w, report); err != nil {
+			return errors.Wrap(err, "failed to render markdown report")
+		}
+	default:
+		return errors.Errorf("unknown format %q", format)
+	}
+
+	return nil
+}
+
+func buildReport(r *runs.Run) (*Report, error) {
+	report := Report{
+		Run:         r,
+		Outcome:     r.Outcome(),
+		Duration:    formatDuration(r.FinishedAt.Sub(r.StartedAt)),
+		GeneratedAt: time.Now(),
+	}
+
+	// only include the steps that ran on at least one cluster
+	for _, step := range types.Steps {
+		for _, c := range r.Clusters {
+			if findStep(c, step) != nil {
+				report.Steps = append(report.Steps, step)
+				break
+			}
+		}
+	}
+
+	for _, c := range r.Clusters {
+		clusterReport := ClusterReport{
+			Name:     c.Name,
+			Provider: c.Provider,
+			Region:   c.Region,
+			Version:  c.Version,
+			Outcome:  c.Outcome,
+		}
+
+		for _, step := range report.Steps {
+			stepResult := findStep(c, step)
+			if stepResult == nil {
+				clusterReport.Cells = append(clusterReport.Cells, Cell{})
+				continue
+			}
+
+			cell := Cell{
+				State:    StatePassed,
+				Duration: formatDuration(stepResult.Duration()),
+			}
+			if stepResult.Error != "" {
+				cell.State = StateFailed
+				clusterReport.Errors = append(clusterReport.Errors, StepError{
+					Step:    step,
+					Message: stepResult.Error,
+				})
+			}
+			clusterReport.Cells = append(clusterReport.Cells, cell)
+		}
+
+		if c.Error != "" && len(clusterReport.Errors) == 0 {
+			clusterReport.Errors = append(clusterReport.Errors, StepError{
+				Message: c.Error,
+			})
+		}
+
+		for _, artifact := range c.Artifacts {
+			if experiment.IsK6Summary(artifact) {
+				summary, err := experiment.ReadK6Summary(artifact)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to read k6 summary for cluster %s", c.Name)
+				}
+				clusterReport.K6Summaries = append(clusterReport.K6Summaries, k6Summary(artifact, summary))
+			} else if strings.HasSuffix(artifact, ".tar.gz") {
+				clusterReport.SupportBundles = append(clusterReport.SupportBundles, artifact)
+			} else {
+				clusterReport.Artifacts = append(clusterReport.Artifacts, artifact)
+			}
+		}
+
+		report.Clusters = append(report.Clusters, clusterReport)
+	}
+
+	return &report, nil
+}
+
+func findStep(c *runs.ClusterRun, name string) *types.StepResult {
+	for i := range c.Steps {
+		if c.Steps[i].Name == name {
+			return &c.Steps[i]
+		}
+	}
+
+	return nil
+}
+
+func k6Summary(path string, summary *experiment.K6Summary) K6Summary {
+	k6 := K6Summary{
+		Path: path,
+	}
+
+	if v, ok := summary.Value("http_reqs", "count"); ok {
+		k6.Requests = fmt.Sprintf("%.0f", v)
+	}
+	if v, ok := summary.Value("http_req_duration", "avg"); ok {
+		k6.RequestDurationAvg = fmt.Sprintf("%.2fms", v)
+	}
+	if v, ok := summary.Value("http_req_duration", "p(95)"); ok {
+		k6.RequestDurationP95 = fmt.Sprintf("%.2fms", v)
+	}
+	if v, ok := summary.Value("checks", "fails"); ok {
+		k6.ChecksFailed = fmt.Sprintf("%.0f", v)
+	}
+
+	return k6
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	return d.Round(time.Second).String()
+}
+
+func markdownCell(cell Cell) string {
+	switch cell.State {
+	case StatePassed:
+		return fmt.Sprintf("✅ %s", cell.Duration)
+	case StateFailed:
+		return fmt.Sprintf("❌ %s", cell.Duration)
+	}
+
+	return "-"
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/replicatedhq/kubectl-grid/pkg/runs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	summaryPath := filepath.Join(dir, "cluster-a-k6-summary.json")
+	err = ioutil.WriteFile(summaryPath, []byte(`{"metrics":{"http_reqs":{"count":1200,"rate":40},"http_req_duration":{"avg":12.5,"p(95)":31.25,"thresholds":{"p(95)<500":false}},"checks":{"passes":1200,"fails":0}}}`), 0644)
+	require.NoError(t, err)
+
+	startedAt := time.Date(2021, 1, 15, 9, 30, 0, 0, time.UTC)
+	step := func(name string, seconds int, errMsg string) types.StepResult {
+		return types.StepResult{
+			Name:       name,
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Duration(seconds) * time.Second),
+			Error:      errMsg,
+		}
+	}
+
+	r := &runs.Run{
+		ID:         "20210115-093000-abcdef",
+		Kind:       runs.KindDeploy,
+		Grid:       "my-grid",
+		Name:       "my-app",
+		SpecHash:   "0123456789ab",
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(5 * time.Minute),
+		Clusters: []*runs.ClusterRun{
+			{
+				Name:      "cluster-a",
+				Provider:  "aws",
+				Region:    "us-west-1",
+				Version:   "1.18",
+				Outcome:   runs.OutcomeSucceeded,
+				Steps:     []types.StepResult{step(types.StepDeploy, 60, ""), step(types.StepReadiness, 90, "")},
+				Artifacts: []string{summaryPath},
+			},
+			{
+				Name:      "cluster-b",
+				Provider:  "aws",
+				Region:    "us-east-1",
+				Version:   "1.17",
+				Outcome:   runs.OutcomeFailed,
+				Error:     "preflight policy: <check> failed",
+				Steps:     []types.StepResult{step(types.StepDeploy, 60, ""), step(types.StepPreflights, 30, "preflight policy: <check> failed")},
+				Artifacts: []string{"/tmp/support-bundles/my-grid/cluster-b.tar.gz"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected []string
+	}{
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			expected: []string{
+				"| Cluster | Provider | Region | Version | deploy | preflights | readiness |",
+				"| cluster-a | aws | us-west-1 | 1.18 | ✅ 1m0s | - | ✅ 1m30s |",
+				"| cluster-b | aws | us-east-1 | 1.17 | ✅ 1m0s | ❌ 30s | - |",
+				"**preflights error:**",
+				"| 1200 | 12.50ms | 31.25ms | 0 |",
+				"- [/tmp/support-bundles/my-grid/cluster-b.tar.gz](/tmp/support-bundles/my-grid/cluster-b.tar.gz)",
+			},
+		},
+		{
+			name:   "html",
+			format: FormatHTML,
+			expected: []string{
+				"<th>deploy</th><th>preflights</th><th>readiness</th>",
+				`<td class="failed">failed 30s</td>`,
+				"preflight policy: &lt;check&gt; failed",
+				"<td>31.25ms</td>",
+				`<a href="file:///tmp/support-bundles/my-grid/cluster-b.tar.gz">`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Generate(r, test.format, &out)
+			require.NoError(t, err)
+
+			for _, expected := range test.expected {
+				assert.Contains(t, out.String(), expected)
+			}
+		})
+	}
+
+	err = Generate(r, "pdf", &bytes.Buffer{})
+	assert.Error(t, err)
+}
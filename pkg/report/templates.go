@@ -0,0 +1,104 @@
+package report
+
+// the templates are compiled in so that reports can be generated without network access
+
+const markdownTemplate = `# Grid run {{ .Run.ID }}
+
+| | |
+|---|---|
+| Grid | {{ .Run.Grid }} |
+| {{ if eq .Run.Kind "experiment" }}Experiment{{ else }}App{{ end }} | {{ .Run.Name }} |
+| Spec | {{ .Run.SpecHash }} |
+| Started | {{ .Run.StartedAt.Format "2006-01-02 15:04:05 MST" }} |
+| Duration | {{ .Duration }} |
+| Outcome | {{ .Outcome }} |
+
+## Results
+
+| Cluster | Provider | Region | Version |{{ range .Steps }} {{ . }} |{{ end }}
+|---|---|---|---|{{ range .Steps }}---|{{ end }}
+{{ range .Clusters }}| {{ .Name }} | {{ .Provider }} | {{ .Region }} | {{ .Version }} |{{ range .Cells }} {{ cell . }} |{{ end }}
+{{ end }}
+{{- range .Clusters }}{{ if or .Errors .K6Summaries .SupportBundles .Artifacts }}
+## {{ .Name }}
+{{ range .Errors }}
+**{{ if .Step }}{{ .Step }} {{ end }}error:**
+
+` + "```" + `
+{{ .Message }}
+` + "```" + `
+{{ end }}{{ range .K6Summaries }}
+**k6 summary** ([{{ .Path }}]({{ .Path }}))
+
+| Requests | Avg duration | p95 duration | Failed checks |
+|---|---|---|---|
+| {{ .Requests }} | {{ .RequestDurationAvg }} | {{ .RequestDurationP95 }} | {{ .ChecksFailed }} |
+{{ end }}{{ if .SupportBundles }}
+**Support bundles**
+{{ range .SupportBundles }}
+- [{{ . }}]({{ . }}){{ end }}
+{{ end }}{{ if .Artifacts }}
+**Artifacts**
+{{ range .Artifacts }}
+- [{{ . }}]({{ . }}){{ end }}
+{{ end }}{{ end }}{{ end }}
+_Generated {{ .GeneratedAt.Format "2006-01-02 15:04:05 MST" }}_
+`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Grid run {{ .Run.ID }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d1d5da; padding: 6px 12px; text-align: left; }
+th { background: #f6f8fa; }
+td.passed { background: #dcffe4; }
+td.failed { background: #ffdce0; }
+td.skipped { color: #959da5; }
+pre { background: #f6f8fa; padding: 12px; overflow-x: auto; white-space: pre-wrap; }
+.outcome-succeeded { color: #22863a; }
+.outcome-failed { color: #cb2431; }
+</style>
+</head>
+<body>
+<h1>Grid run {{ .Run.ID }}</h1>
+<table>
+<tr><th>Grid</th><td>{{ .Run.Grid }}</td></tr>
+<tr><th>{{ if eq .Run.Kind "experiment" }}Experiment{{ else }}App{{ end }}</th><td>{{ .Run.Name }}</td></tr>
+<tr><th>Spec</th><td>{{ .Run.SpecHash }}</td></tr>
+<tr><th>Started</th><td>{{ .Run.StartedAt.Format "2006-01-02 15:04:05 MST" }}</td></tr>
+<tr><th>Duration</th><td>{{ .Duration }}</td></tr>
+<tr><th>Outcome</th><td class="outcome-{{ .Outcome }}">{{ .Outcome }}</td></tr>
+</table>
+
+<h2>Results</h2>
+<table>
+<tr><th>Cluster</th><th>Provider</th><th>Region</th><th>Version</th>{{ range .Steps }}<th>{{ . }}</th>{{ end }}</tr>
+{{ range .Clusters }}<tr><td>{{ .Name }}</td><td>{{ .Provider }}</td><td>{{ .Region }}</td><td>{{ .Version }}</td>{{ range .Cells }}{{ if .State }}<td class="{{ .State }}">{{ .State }} {{ .Duration }}</td>{{ else }}<td class="skipped">-</td>{{ end }}{{ end }}</tr>
+{{ end }}</table>
+{{ range .Clusters }}{{ if or .Errors .K6Summaries .SupportBundles .Artifacts }}
+<h2>{{ .Name }}</h2>
+{{ range .Errors }}<h3>{{ if .Step }}{{ .Step }} {{ end }}error</h3>
+<pre>{{ .Message }}</pre>
+{{ end }}{{ range .K6Summaries }}<h3>k6 summary</h3>
+<table>
+<tr><th>Requests</th><th>Avg duration</th><th>p95 duration</th><th>Failed checks</th></tr>
+<tr><td>{{ .Requests }}</td><td>{{ .RequestDurationAvg }}</td><td>{{ .RequestDurationP95 }}</td><td>{{ .ChecksFailed }}</td></tr>
+</table>
+<p><a href="file://{{ .Path }}">{{ .Path }}</a></p>
+{{ end }}{{ if .SupportBundles }}<h3>Support bundles</h3>
+<ul>
+{{ range .SupportBundles }}<li><a href="file://{{ . }}">{{ . }}</a></li>
+{{ end }}</ul>
+{{ end }}{{ if .Artifacts }}<h3>Artifacts</h3>
+<ul>
+{{ range .Artifacts }}<li><a href="file://{{ . }}">{{ . }}</a></li>
+{{ end }}</ul>
+{{ end }}{{ end }}{{ end }}
+<p><em>Generated {{ .GeneratedAt.Format "2006-01-02 15:04:05 MST" }}</em></p>
+</body>
+</html>
+`
@@ -0,0 +1,107 @@
+package runs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+)
+
+const (
+	KindDeploy     = "deploy"
+	KindExperiment = "experiment"
+
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// Run is the record of a single deploy or experiment on a grid
+type Run struct {
+	ID         string        `json:"id"`
+	Kind       string        `json:"kind"`
+	Grid       string        `json:"grid"`
+	Name       string        `json:"name"`
+	SpecHash   string        `json:"specHash"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt time.Time     `json:"finishedAt,omitempty"`
+	Clusters   []*ClusterRun `json:"clusters,omitempty"`
+}
+
+type ClusterRun struct {
+	Name       string             `json:"name"`
+	Provider   string             `json:"provider"`
+	Region     string             `json:"region"`
+	Version    string             `json:"version,omitempty"`
+	Outcome    string             `json:"outcome"`
+	Error      string             `json:"error,omitempty"`
+	StartedAt  time.Time          `json:"startedAt,omitempty"`
+	FinishedAt time.Time          `json:"finishedAt,omitempty"`
+	Steps      []types.StepResult `json:"steps,omitempty"`
+	// LogsPath and Artifacts are paths to copies in the run directory
+	LogsPath         string                  `json:"logsPath,omitempty"`
+	Artifacts        []string                `json:"artifacts,omitempty"`
+	PreflightResults []types.PreflightResult `json:"preflightResults,omitempty"`
+	CheckResults     []types.CheckResult     `json:"checkResults,omitempty"`
+}
+
+// NewRun starts a run record for the spec. The spec hash identifies runs of the same app or
+// experiment, so that results can be compared
+func NewRun(kind string, gridName string, name string, spec []byte) *Run {
+	startedAt := time.Now()
+
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+
+	return &Run{
+		ID:        fmt.Sprintf("%s-%x", startedAt.Format("20060102-150405"), suffix),
+		Kind:      kind,
+		Grid:      gridName,
+		Name:      name,
+		SpecHash:  fmt.Sprintf("%x", sha256.Sum256(spec))[:12],
+		StartedAt: startedAt,
+	}
+}
+
+// NewClusterRun returns a cluster run with the details of the cluster
+func NewClusterRun(c *types.ClusterConfig) *ClusterRun {
+	return &ClusterRun{
+		Name:     c.Name,
+		Provider: c.Provider,
+		Region:   c.Region,
+		Version:  c.Version,
+	}
+}
+
+// SetError records the outcome of the cluster run
+func (c *ClusterRun) SetError(errMsg string) {
+	if errMsg != "" {
+		c.Outcome = OutcomeFailed
+		c.Error = errMsg
+		return
+	}
+
+	c.Outcome = OutcomeSucceeded
+}
+
+// Outcome is failed if any cluster failed
+func (r *Run) Outcome() string {
+	for _, c := range r.Clusters {
+		if c.Outcome != OutcomeSucceeded {
+			return OutcomeFailed
+		}
+	}
+
+	return OutcomeSucceeded
+}
+
+func (r *Run) GetCluster(name string) *ClusterRun {
+	for _, c := range r.Clusters {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
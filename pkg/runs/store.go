@@ -0,0 +1,147 @@
+package runs
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dir returns the directory runs are stored in, next to the grid config file
+func Dir(configFilePath string) string {
+	return filepath.Join(filepath.Dir(configFilePath), "runs")
+}
+
+// Save writes the run record to <dir>/<run id>/run.json
+func Save(dir string, r *Run) error {
+	runDir := filepath.Join(dir, r.ID)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create run dir")
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal run")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(runDir, "run.json"), b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write run")
+	}
+
+	return nil
+}
+
+// Get will return the run with the id, or the only run with the id as a prefix
+func Get(dir string, id string) (*Run, error) {
+	allRuns, err := List(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list runs")
+	}
+
+	matches := []*Run{}
+	for _, r := range allRuns {
+		if r.ID == id {
+			return r, nil
+		}
+		if strings.HasPrefix(r.ID, id) {
+			matches = append(matches, r)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.Errorf("run %s not found", id)
+	}
+	if len(matches) > 1 {
+		return nil, errors.Errorf("run id %s is ambiguous", id)
+	}
+
+	return matches[0], nil
+}
+
+// List returns all runs, most recent first
+func List(dir string) ([]*Run, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Run{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to read runs dir")
+	}
+
+	allRuns := []*Run{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, entry.Name(), "run.json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to read run %s", entry.Name())
+		}
+
+		r := Run{}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal run %s", entry.Name())
+		}
+		allRuns = append(allRuns, &r)
+	}
+
+	sort.Slice(allRuns, func(i, j int) bool {
+		return allRuns[i].StartedAt.After(allRuns[j].StartedAt)
+	})
+
+	return allRuns, nil
+}
+
+// WriteClusterLogs stores the logs for the cluster in the run dir
+func WriteClusterLogs(dir string, r *Run, c *ClusterRun, logs []byte) error {
+	clusterDir := filepath.Join(dir, r.ID, c.Name)
+	if err := os.MkdirAll(clusterDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create cluster dir")
+	}
+
+	logsPath := filepath.Join(clusterDir, "logs.txt")
+	if err := ioutil.WriteFile(logsPath, logs, 0644); err != nil {
+		return errors.Wrap(err, "failed to write logs")
+	}
+	c.LogsPath = logsPath
+
+	return nil
+}
+
+// AddArtifact copies the file into the run dir, so that it is kept when the
+// output dir is cleaned up
+func AddArtifact(dir string, r *Run, c *ClusterRun, srcPath string) error {
+	clusterDir := filepath.Join(dir, r.ID, c.Name)
+	if err := os.MkdirAll(clusterDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create cluster dir")
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open artifact")
+	}
+	defer src.Close()
+
+	artifactPath := filepath.Join(clusterDir, filepath.Base(srcPath))
+	dst, err := os.Create(artifactPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create artifact")
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return errors.Wrap(err, "failed to copy artifact")
+	}
+	c.Artifacts = append(c.Artifacts, artifactPath)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package runs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/grid/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Store(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "runs")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	artifactPath := filepath.Join(dir, "cluster-a-k6-summary.json")
+	req.NoError(ioutil.WriteFile(artifactPath, []byte(`{}`), 0644))
+
+	older := NewRun(KindDeploy, "grid", "app", []byte("spec"))
+	older.StartedAt = older.StartedAt.Add(-time.Hour)
+	older.ID = "older"
+	req.NoError(Save(dir, older))
+
+	newer := NewRun(KindExperiment, "grid", "load-test", []byte("spec"))
+	clusterRun := NewClusterRun(&types.ClusterConfig{Name: "cluster-a", Provider: "aws"})
+	clusterRun.SetError("")
+	req.NoError(WriteClusterLogs(dir, newer, clusterRun, []byte("hello\n")))
+	req.NoError(AddArtifact(dir, newer, clusterRun, artifactPath))
+	newer.Clusters = append(newer.Clusters, clusterRun)
+	req.NoError(Save(dir, newer))
+
+	allRuns, err := List(dir)
+	req.NoError(err)
+	req.Len(allRuns, 2)
+	assert.Equal(t, newer.ID, allRuns[0].ID)
+	assert.Equal(t, "older", allRuns[1].ID)
+	assert.Equal(t, older.SpecHash, newer.SpecHash)
+
+	r, err := Get(dir, newer.ID[:8])
+	req.NoError(err)
+	assert.Equal(t, OutcomeSucceeded, r.Outcome())
+	req.Len(r.Clusters[0].Artifacts, 1)
+	assert.FileExists(t, r.Clusters[0].Artifacts[0])
+
+	logs, err := ioutil.ReadFile(r.GetCluster("cluster-a").LogsPath)
+	req.NoError(err)
+	assert.Equal(t, "hello\n", string(logs))
+
+	_, err = Get(dir, "missing")
+	req.Error(err)
+}
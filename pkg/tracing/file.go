@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// FileSpan is written as a single line for each span
+type FileSpan struct {
+	Name            string            `json:"name"`
+	TraceID         string            `json:"traceId"`
+	SpanID          string            `json:"spanId"`
+	ParentSpanID    string            `json:"parentSpanId,omitempty"`
+	StartTime       time.Time         `json:"startTime"`
+	EndTime         time.Time         `json:"endTime"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	Error           string            `json:"error,omitempty"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+}
+
+// FileExporter writes each span as a JSON object on its own line, so that traces can be
+// looked at without a collector
+type FileExporter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+
+	return &FileExporter{
+		f: f,
+	}, nil
+}
+
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		b, err := json.Marshal(newFileSpan(span))
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal span")
+		}
+		if _, err := fmt.Fprintln(e.f, string(b)); err != nil {
+			return errors.Wrap(err, "failed to write span")
+		}
+	}
+
+	return nil
+}
+
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.f.Close()
+}
+
+func newFileSpan(span sdktrace.ReadOnlySpan) FileSpan {
+	fileSpan := FileSpan{
+		Name:            span.Name(),
+		TraceID:         span.SpanContext().TraceID().String(),
+		SpanID:          span.SpanContext().SpanID().String(),
+		StartTime:       span.StartTime(),
+		EndTime:         span.EndTime(),
+		DurationSeconds: span.EndTime().Sub(span.StartTime()).Seconds(),
+	}
+	if span.Parent().IsValid() {
+		fileSpan.ParentSpanID = span.Parent().SpanID().String()
+	}
+	if span.Status().Code == codes.Error {
+		fileSpan.Error = span.Status().Description
+	}
+
+	for _, kv := range span.Attributes() {
+		if fileSpan.Attributes == nil {
+			fileSpan.Attributes = map[string]string{}
+		}
+		fileSpan.Attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+
+	return fileSpan
+}
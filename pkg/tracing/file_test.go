@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func Test_FileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubectl-grid")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trace.json")
+
+	exporter, err := NewFileExporter(path)
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tp.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "create")
+	_, child := tracer.Start(ctx, "cluster a")
+	child.SetAttributes(ClusterKey.String("a"))
+	End(child, errors.New("node group did not become active"))
+	End(parent, nil)
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	spans := []FileSpan{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		span := FileSpan{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans = append(spans, span)
+	}
+	require.Len(t, spans, 2)
+
+	// spans are written when they end, so the child is first
+	assert.Equal(t, "cluster a", spans[0].Name)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	assert.Equal(t, "node group did not become active", spans[0].Error)
+	assert.Equal(t, map[string]string{"cluster": "a"}, spans[0].Attributes)
+
+	assert.Equal(t, "create", spans[1].Name)
+	assert.Empty(t, spans[1].ParentSpanID)
+	assert.Empty(t, spans[1].Error)
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/replicatedhq/kubectl-grid"
+
+// The attributes that are set on spans, named the same as the logger fields
+const (
+	GridKey    = attribute.Key("grid")
+	ClusterKey = attribute.Key("cluster")
+	AppKey     = attribute.Key("app")
+	ChannelKey = attribute.Key("channel")
+	StepKey    = attribute.Key("step")
+)
+
+type Options struct {
+	// Endpoint is the host:port of an OTLP gRPC collector to export spans to
+	Endpoint string
+	// Insecure will connect to the collector without TLS
+	Insecure bool
+	// File is the path to write spans to as JSON lines, instead of exporting them to a collector
+	File string
+}
+
+// Init will export the spans that are started after it returns, when opts has an endpoint
+// or a file. Spans are not recorded when it is not called. shutdown must be called before
+// exiting to export the spans that are still buffered
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	if opts.File != "" {
+		e, err := NewFileExporter(opts.File)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create file exporter")
+		}
+		exporter = e
+	} else if opts.Endpoint != "" {
+		clientOpts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(opts.Endpoint),
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		e, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create otlp exporter")
+		}
+		exporter = e
+	} else {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("kubectl-grid"))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Start will start a span that is a child of the span in ctx, or the root of a new trace
+// when ctx has no span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End will end the span, recording err on it when the operation failed
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,359 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+)
+
+const (
+	refreshInterval  = 100 * time.Millisecond
+	maxMessageLength = 80
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Table shows the progress of the task on each cluster. When live, it's a table with a row
+// for each cluster that is redrawn in place. Otherwise, each update is written as a line
+// with the cluster name as a prefix, so that it can be read in CI logs
+type Table struct {
+	mu    sync.Mutex
+	out   io.Writer
+	live  bool
+	rows  []*row
+	now   func() time.Time
+	frame int
+	// drawnLines is the number of lines the table used when it was last drawn
+	drawnLines int
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+type row struct {
+	cluster    string
+	step       string
+	message    string
+	startedAt  time.Time
+	finishedAt time.Time
+	err        error
+}
+
+func NewTable(out io.Writer, live bool) *Table {
+	return &Table{
+		out:  out,
+		live: live,
+		now:  time.Now,
+	}
+}
+
+type tableKey struct{}
+
+type outputKey struct{}
+
+// NewContext returns a context that reports the tasks run with it to the table
+func NewContext(ctx context.Context, t *Table) context.Context {
+	ctx = orchestrator.WithObserver(ctx, t)
+	return context.WithValue(ctx, tableKey{}, t)
+}
+
+// FromContext returns the table in ctx, or nil when the progress is not being watched
+func FromContext(ctx context.Context) *Table {
+	t, _ := ctx.Value(tableKey{}).(*Table)
+	return t
+}
+
+// WithOutput returns a context that prints the messages that are not watched to out,
+// instead of stdout
+func WithOutput(ctx context.Context, out io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, out)
+}
+
+// Output returns where the messages that are not watched are printed
+func Output(ctx context.Context) io.Writer {
+	if out, ok := ctx.Value(outputKey{}).(io.Writer); ok {
+		return out
+	}
+	return os.Stdout
+}
+
+// Step records the step that the task in ctx is running
+func Step(ctx context.Context, step string) {
+	t, cluster := FromContext(ctx), orchestrator.ClusterFromContext(ctx)
+	if t == nil || cluster == "" {
+		return
+	}
+
+	t.update(cluster, func(r *row) {
+		r.step = step
+		r.message = ""
+	}, fmt.Sprintf("step: %s", step))
+}
+
+// Message records the last message from the task in ctx
+func Message(ctx context.Context, msg string, args ...interface{}) {
+	t, cluster := FromContext(ctx), orchestrator.ClusterFromContext(ctx)
+	if t == nil || cluster == "" {
+		return
+	}
+
+	t.Message(cluster, fmt.Sprintf(msg, args...))
+}
+
+// Printf will record the message from the task in ctx when the progress is being watched,
+// or print it otherwise. Messages that are not from a task are printed above the table
+func Printf(ctx context.Context, msg string, args ...interface{}) {
+	t, cluster := FromContext(ctx), orchestrator.ClusterFromContext(ctx)
+	if t == nil {
+		fmt.Fprintf(Output(ctx), msg, args...)
+		return
+	}
+	if cluster == "" {
+		t.Println(fmt.Sprintf(strings.TrimSuffix(msg, "\n"), args...))
+		return
+	}
+
+	Message(ctx, strings.TrimSuffix(msg, "\n"), args...)
+}
+
+// Writer returns a writer that records each line written to it as a message from the task in ctx
+func Writer(ctx context.Context) io.Writer {
+	return &messageWriter{
+		ctx: ctx,
+	}
+}
+
+type messageWriter struct {
+	ctx context.Context
+	buf bytes.Buffer
+}
+
+func (w *messageWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// put the partial line back until the rest of it is written
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+
+		if line = strings.TrimSpace(line); line != "" {
+			Message(w.ctx, "%s", line)
+		}
+	}
+
+	return len(b), nil
+}
+
+// Start will draw the table until Stop is called
+func (t *Table) Start() {
+	if !t.live {
+		return
+	}
+
+	t.stopCh = make(chan struct{})
+	t.doneCh = make(chan struct{})
+	go func() {
+		defer close(t.doneCh)
+		for {
+			select {
+			case <-t.stopCh:
+				return
+			case <-time.After(refreshInterval):
+				t.mu.Lock()
+				t.frame++
+				t.draw()
+				t.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop will draw the table a final time, followed by the errors from each failed cluster
+func (t *Table) Stop() {
+	if t.stopCh != nil {
+		close(t.stopCh)
+		<-t.doneCh
+		t.stopCh = nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.live {
+		t.draw()
+	}
+
+	for _, r := range t.rows {
+		if r.err == nil {
+			continue
+		}
+		fmt.Fprintf(t.out, "\nCluster %s failed", r.cluster)
+		if r.step != "" {
+			fmt.Fprintf(t.out, " during %s", r.step)
+		}
+		fmt.Fprintf(t.out, ":\n    %s\n", r.err.Error())
+	}
+}
+
+// Println prints line above the table, so that it's not overwritten when the table is redrawn
+func (t *Table) Println(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.live {
+		fmt.Fprintln(t.out, line)
+		return
+	}
+
+	if t.drawnLines == 0 {
+		fmt.Fprintln(t.out, line)
+		return
+	}
+
+	// clear the table, print the line where it was, and draw the table again below it
+	fmt.Fprintf(t.out, "\033[%dA\033[J", t.drawnLines)
+	t.drawnLines = 0
+	fmt.Fprintln(t.out, line)
+	t.draw()
+}
+
+// Message shows message as the last message of the cluster
+func (t *Table) Message(cluster string, message string) {
+	t.update(cluster, func(r *row) {
+		r.message = message
+	}, message)
+}
+
+func (t *Table) TaskQueued(cluster string) {
+	t.update(cluster, func(r *row) {
+		*r = row{cluster: cluster}
+	}, "")
+}
+
+func (t *Table) TaskStarted(cluster string) {
+	t.update(cluster, func(r *row) {
+		r.startedAt = t.now()
+	}, "started")
+}
+
+func (t *Table) TaskFinished(cluster string, err error) {
+	msg := "finished"
+	if err != nil {
+		msg = fmt.Sprintf("failed: %s", err.Error())
+	}
+
+	t.update(cluster, func(r *row) {
+		r.finishedAt = t.now()
+		r.err = err
+	}, msg)
+}
+
+// update will call fn with the row for the cluster. When the table is not live, line is
+// written with the cluster name as a prefix
+func (t *Table) update(cluster string, fn func(r *row), line string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var r *row
+	for _, existing := range t.rows {
+		if existing.cluster == cluster {
+			r = existing
+		}
+	}
+	if r == nil {
+		r = &row{cluster: cluster}
+		t.rows = append(t.rows, r)
+	}
+
+	fn(r)
+
+	if !t.live && line != "" {
+		fmt.Fprintf(t.out, "[%s] %s\n", cluster, line)
+	}
+}
+
+// draw will replace the table that was drawn last with the current state
+func (t *Table) draw() {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tSTEP\tELAPSED\tSTATUS\tMESSAGE")
+	for _, r := range t.rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.cluster, valueOrDash(r.step), t.elapsed(r), t.status(r), truncate(t.lastMessage(r)))
+	}
+	w.Flush()
+
+	// move the cursor to the start of the previous table and clear each line as it's redrawn
+	if t.drawnLines > 0 {
+		fmt.Fprintf(t.out, "\033[%dA", t.drawnLines)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		fmt.Fprintf(t.out, "\033[2K%s\n", line)
+	}
+	t.drawnLines = len(lines)
+}
+
+func (t *Table) elapsed(r *row) string {
+	if r.startedAt.IsZero() {
+		return "-"
+	}
+
+	finishedAt := r.finishedAt
+	if finishedAt.IsZero() {
+		finishedAt = t.now()
+	}
+
+	return finishedAt.Sub(r.startedAt).Round(time.Second).String()
+}
+
+func (t *Table) status(r *row) string {
+	switch {
+	case r.err != nil:
+		return "✗"
+	case !r.finishedAt.IsZero():
+		return "✓"
+	case r.startedAt.IsZero():
+		return "…"
+	default:
+		return spinnerFrames[t.frame%len(spinnerFrames)]
+	}
+}
+
+func (t *Table) lastMessage(r *row) string {
+	if r.err != nil {
+		return r.err.Error()
+	}
+	return r.message
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func truncate(message string) string {
+	message = strings.Join(strings.Fields(message), " ")
+	runes := []rune(message)
+	if len(runes) > maxMessageLength {
+		return string(runes[:maxMessageLength-3]) + "..."
+	}
+	return message
+}
@@ -0,0 +1,128 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kubectl-grid/pkg/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTasks(t *testing.T, table *Table) {
+	ctx := NewContext(context.Background(), table)
+
+	tasks := []orchestrator.Task{
+		{
+			Cluster: "a",
+			Run: func(ctx context.Context) error {
+				Step(ctx, "deploy")
+				Message(ctx, "installing %s", "app")
+				return nil
+			},
+		},
+		{
+			Cluster: "b",
+			Run: func(ctx context.Context) error {
+				Step(ctx, "readiness")
+				w := Writer(ctx)
+				w.Write([]byte("waiting for "))
+				w.Write([]byte("pods\nstill waiting\n"))
+				return errors.New("timed out")
+			},
+		},
+	}
+
+	_, err := orchestrator.Run(ctx, tasks, orchestrator.Options{Parallelism: 1})
+	require.Error(t, err)
+}
+
+func Test_TableLines(t *testing.T) {
+	var out bytes.Buffer
+	table := NewTable(&out, false)
+
+	table.Start()
+	runTasks(t, table)
+	table.Stop()
+
+	expected := `[a] started
+[a] step: deploy
+[a] installing app
+[a] finished
+[b] started
+[b] step: readiness
+[b] waiting for pods
+[b] still waiting
+[b] failed: timed out
+
+Cluster b failed during readiness:
+    timed out
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func Test_TableLive(t *testing.T) {
+	var out bytes.Buffer
+	table := NewTable(&out, true)
+	now := time.Date(2021, 1, 15, 9, 30, 0, 0, time.UTC)
+	table.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	runTasks(t, table)
+	table.Stop()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.True(t, len(lines) >= 3)
+	assert.Contains(t, lines[0], "CLUSTER")
+	assert.Contains(t, lines[1], "a")
+	assert.Contains(t, lines[1], "deploy")
+	assert.Contains(t, lines[1], "✓")
+	assert.Contains(t, lines[1], "installing app")
+	assert.Contains(t, lines[2], "readiness")
+	assert.Contains(t, lines[2], "✗")
+	assert.Contains(t, lines[2], "timed out")
+	assert.Contains(t, out.String(), "Cluster b failed during readiness:\n    timed out\n")
+}
+
+func Test_truncate(t *testing.T) {
+	assert.Equal(t, "a b", truncate("a\n  b"))
+	assert.Equal(t, 80, len([]rune(truncate(strings.Repeat("✓", 100)))))
+}
+
+func Test_PrintfWithoutCluster(t *testing.T) {
+	var out bytes.Buffer
+	table := NewTable(&out, false)
+	ctx := NewContext(context.Background(), table)
+
+	Printf(ctx, "deleting the clusters in grid %s\n", "grid")
+	assert.Equal(t, "deleting the clusters in grid grid\n", out.String())
+
+	out.Reset()
+	table = NewTable(&out, true)
+	ctx = NewContext(context.Background(), table)
+	table.TaskQueued("a")
+	table.mu.Lock()
+	table.draw()
+	table.mu.Unlock()
+
+	Printf(ctx, "interrupted\n")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[2], "interrupted")
+	assert.Contains(t, lines[3], "CLUSTER")
+	assert.Contains(t, lines[4], "a")
+}
+
+func Test_PrintfOutput(t *testing.T) {
+	var out bytes.Buffer
+	ctx := WithOutput(context.Background(), &out)
+
+	Printf(ctx, "installed %s\n", "app")
+	assert.Equal(t, "installed app\n", out.String())
+}